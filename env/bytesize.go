@@ -0,0 +1,112 @@
+package env
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes parsed from human readable values like "512", "64MiB", "1.5GB" or "10Ki".
+// Binary units (KiB, MiB, ...) are powers of 1024, decimal units (KB, MB, ...) are powers of 1000.
+type ByteSize uint64
+
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+var byteUnits = map[string]ByteSize{
+	"":  Byte,
+	"b": Byte,
+	"k": KB, "kb": KB,
+	"m": MB, "mb": MB,
+	"g": GB, "gb": GB,
+	"t": TB, "tb": TB,
+	"p": PB, "pb": PB,
+	"ki": KiB, "kib": KiB,
+	"mi": MiB, "mib": MiB,
+	"gi": GiB, "gib": GiB,
+	"ti": TiB, "tib": TiB,
+	"pi": PiB, "pib": PiB,
+}
+
+var errByteSize = errors.New("expected a size like 512, 64MiB or 1.5GB")
+
+// ParseByteSize parses a human readable size
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok || i == 0 {
+		return 0, errByteSize
+	}
+	num := s[:i]
+	if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if n > math.MaxUint64/uint64(unit) {
+			return 0, strconv.ErrRange
+		}
+		return ByteSize(n) * unit, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errByteSize
+	}
+	f *= float64(unit)
+	if f >= math.MaxUint64 {
+		return 0, strconv.ErrRange
+	}
+	return ByteSize(f), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	v, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// String formats the size with the largest binary unit dividing it evenly
+func (b ByteSize) String() string {
+	units := [...]struct {
+		size ByteSize
+		name string
+	}{
+		{PiB, "PiB"}, {TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"},
+	}
+	for _, u := range units {
+		if b >= u.size && b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// Bytes returns the size as a number of bytes
+func (b ByteSize) Bytes() uint64 {
+	return uint64(b)
+}
@@ -0,0 +1,262 @@
+package env
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// ErrDotenv is returned for malformed dotenv content
+var ErrDotenv = errors.New("malformed dotenv")
+
+// FileSource is a source backed by dotenv files, later files override earlier ones
+type FileSource struct {
+	paths    []string
+	optional bool // missing files are skipped rather than failing
+	mu       sync.RWMutex
+	values   Map
+	stamps   []fileStamp
+}
+
+// File reads the given dotenv files into a source.
+// Variable references are expanded against the process environment first and
+// then against the values defined above them, matching the lookup precedence.
+func File(paths ...string) (*FileSource, error) {
+	s := &FileSource{
+		paths:  paths,
+		stamps: stampFiles(paths),
+	}
+	values, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.values = values
+	return s, nil
+}
+
+func (s *FileSource) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values.Lookup(key)
+}
+
+func (s *FileSource) read() (Map, error) {
+	values := make(Map)
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if s.optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := parseDotenv(string(data), Chain(OS(), values), values); err != nil {
+			return nil, xerrors.Err(err).Str("path", path).Send()
+		}
+	}
+	return values, nil
+}
+
+// ParseDotenv parses dotenv content from r.
+// References like $VAR, ${VAR}, ${VAR:-default} and ${VAR-default} are resolved
+// through expand first and then through the values defined above, expand may be nil.
+func ParseDotenv(r io.Reader, expand Source) (Map, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	values := make(Map)
+	if expand == nil {
+		expand = values
+	} else {
+		expand = Chain(expand, values)
+	}
+	if err := parseDotenv(string(data), expand, values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseDotenv supports comments, blank lines, export prefixes,
+// single quoted (literal), double quoted (escaped, multiline) and bare values
+func parseDotenv(src string, expand Source, dst Map) error {
+	line := 1
+	for len(src) > 0 {
+		src = strings.TrimLeft(src, " \t\r")
+		if src == "" {
+			break
+		}
+		switch src[0] {
+		case '\n':
+			src = src[1:]
+			line++
+			continue
+		case '#':
+			src = skipLine(src)
+			line++
+			continue
+		}
+		if rest, ok := strings.CutPrefix(src, "export "); ok {
+			src = strings.TrimLeft(rest, " \t")
+		}
+
+		eq := strings.IndexAny(src, "=\n")
+		if eq < 0 || src[eq] != '=' {
+			return dotenvErr(line, "expected KEY=VALUE")
+		}
+		key := strings.TrimSpace(src[:eq])
+		if !isDotenvKey(key) {
+			return dotenvErr(line, "invalid key "+key)
+		}
+		src = strings.TrimLeft(src[eq+1:], " \t")
+
+		var (
+			val string
+			err error
+		)
+		switch {
+		case strings.HasPrefix(src, "'"):
+			val, src, err = cutQuoted(src[1:], '\'')
+			line += strings.Count(val, "\n")
+		case strings.HasPrefix(src, `"`):
+			val, src, err = cutQuoted(src[1:], '"')
+			line += strings.Count(val, "\n")
+			if err == nil {
+				val = interpolate(val, expand, true)
+			}
+		default:
+			end := strings.IndexByte(src, '\n')
+			if end < 0 {
+				end = len(src)
+			}
+			val, src = src[:end], src[end:]
+			if i := strings.Index(val, " #"); i >= 0 {
+				val = val[:i]
+			}
+			val = interpolate(strings.TrimSpace(val), expand, false)
+		}
+		if err != nil {
+			return dotenvErr(line, err.Error())
+		}
+		dst[key] = val
+		src = skipLine(src)
+		line++
+	}
+	return nil
+}
+
+func dotenvErr(line int, reason string) error {
+	return xerrors.Err(ErrDotenv).
+		Int("line", line).
+		Msg(reason)
+}
+
+// skipLine drops everything up to and including the next line break
+func skipLine(src string) string {
+	if i := strings.IndexByte(src, '\n'); i >= 0 {
+		return src[i+1:]
+	}
+	return ""
+}
+
+// cutQuoted returns the quoted value up to the closing quote and the remainder after it
+func cutQuoted(src string, quote byte) (string, string, error) {
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return src[:i], src[i+1:], nil
+		}
+	}
+	return "", "", errors.New("unterminated quoted value")
+}
+
+// interpolate resolves $VAR, ${VAR}, ${VAR:-default} and ${VAR-default}, \$ yields a literal dollar.
+// With escapes set the double quoted sequences \n, \r, \t, \" and \\ are decoded as well.
+func interpolate(s string, src Source, escapes bool) string {
+	if !strings.ContainsAny(s, `$\`) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '$' || escapes):
+			i++
+			switch c = s[i]; c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			}
+			b.WriteByte(c)
+		case c != '$' || i+1 == len(s):
+			b.WriteByte(c)
+		case s[i+1] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(expandRef(s[i+2:i+end], src))
+			i += end
+		default:
+			j := i + 1
+			for j < len(s) && isKeyByte(s[j], j == i+1) {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte('$')
+				continue
+			}
+			v, _ := src.Lookup(s[i+1 : j])
+			b.WriteString(v)
+			i = j - 1
+		}
+	}
+	return b.String()
+}
+
+func expandRef(ref string, src Source) string {
+	if name, def, ok := strings.Cut(ref, ":-"); ok {
+		if v, ok := src.Lookup(name); ok && v != "" {
+			return v
+		}
+		return interpolate(def, src, false)
+	}
+	if name, def, ok := strings.Cut(ref, "-"); ok {
+		if v, ok := src.Lookup(name); ok {
+			return v
+		}
+		return interpolate(def, src, false)
+	}
+	v, _ := src.Lookup(ref)
+	return v
+}
+
+func isDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := range len(key) {
+		if !isKeyByte(key[i], i == 0) && key[i] != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isKeyByte(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
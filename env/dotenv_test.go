@@ -0,0 +1,76 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+)
+
+const dotenvContent = `# comment
+export HOST=localhost
+PORT = 5432 # inline comment
+USER='pg $USER'
+PASS="p\"w\\d"
+DSN="postgres://${USER}@${HOST}:${PORT}/${DB:-app}"
+CERT="-----BEGIN-----
+abc
+-----END-----"
+ESCAPED=\$HOME
+EMPTY=
+`
+
+func TestParseDotenv(t *testing.T) {
+	have, err := env.ParseDotenv(strings.NewReader(dotenvContent), env.Map{"DB": ""})
+	require.NoError(t, err)
+	assert.Equal(t, env.Map{
+		"HOST":    "localhost",
+		"PORT":    "5432",
+		"USER":    "pg $USER",
+		"PASS":    `p"w\d`,
+		"DSN":     "postgres://pg $USER@localhost:5432/app",
+		"CERT":    "-----BEGIN-----\nabc\n-----END-----",
+		"ESCAPED": "$HOME",
+		"EMPTY":   "",
+	}, have)
+}
+
+func TestParseDotenv_Malformed(t *testing.T) {
+	_, err := env.ParseDotenv(strings.NewReader("A=1\nB=\"open\n"), nil)
+	assert.ErrorIs(t, err, env.ErrDotenv)
+	assert.Contains(t, err.Error(), "line=2")
+
+	_, err = env.ParseDotenv(strings.NewReader("A=1\nNOVALUE\n"), nil)
+	assert.ErrorIs(t, err, env.ErrDotenv)
+}
+
+func TestLoadDotenv(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	require.NoError(t, os.WriteFile(base, []byte("DOTENV_A=base\nDOTENV_B=base\nDOTENV_C=base\n"), 0o600))
+	require.NoError(t, os.WriteFile(local, []byte("DOTENV_B=local\nDOTENV_C=local\n"), 0o600))
+	t.Setenv("DOTENV_C", "os")
+
+	prev := env.CurrentSource()
+	t.Cleanup(func() { env.SetSource(prev) })
+	files, err := env.LoadDotenv(base, local, filepath.Join(dir, "missing.env"))
+	require.NoError(t, err)
+	assert.False(t, files.Modified())
+
+	assert.Equal(t, "base", env.String("DOTENV_A", "def"))
+	assert.Equal(t, "local", env.String("DOTENV_B", "def"))
+	assert.Equal(t, "os", env.String("DOTENV_C", "def"))
+	assert.Equal(t, "def", env.String("DOTENV_D", "def"))
+}
+
+func TestSetSource(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{"PORT": "9090"}))
+
+	assert.Equal(t, 9090, env.Int("PORT", 8080))
+	assert.Equal(t, "9090", env.Get("PORT", "8080"))
+}
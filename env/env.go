@@ -1,14 +1,17 @@
 package env
 
 import (
-	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// Get returns the environment variable specified by key, or the default value specified by def if empty
+// Get returns the environment variable specified by key, or the default value specified by def if empty.
+// Besides the types of the typed helpers it accepts everything Parse does: time.Time, *url.URL,
+// net.IP, netip.Prefix, ByteSize, typed slices and maps, and any encoding.TextUnmarshaler.
 func Get[T any](key string, def T) T {
+	register(key, def)
 	switch tp := (any)(def).(type) {
 	case string:
 		return (any)(String(key, tp)).(T)
@@ -16,12 +19,6 @@ func Get[T any](key string, def T) T {
 		return (any)(Strings(key, tp)).(T)
 	case int:
 		return (any)(Int(key, tp)).(T)
-	case int8:
-		return (any)(int8(Int(key, int(tp)))).(T)
-	case int16:
-		return (any)(int16(Int(key, int(tp)))).(T)
-	case int32:
-		return (any)(int32(Int(key, int(tp)))).(T)
 	case int64:
 		return (any)(Int64(key, tp)).(T)
 	case uint:
@@ -36,23 +33,37 @@ func Get[T any](key string, def T) T {
 		return (any)(Uint64(key, tp)).(T)
 	case uintptr:
 		return (any)(uintptr(Uint(key, uint(tp)))).(T)
-	case float32:
-		return (any)(float32(Float64(key, float64(tp)))).(T)
 	case float64:
 		return (any)(Float64(key, tp)).(T)
 	case bool:
 		return (any)(Bool(key, tp)).(T)
 	case time.Duration:
 		return (any)(Duration(key, tp)).(T)
+	case time.Time:
+		return (any)(Time(key, tp)).(T)
 	default:
-		panic("Env: unsupported parameter type")
+		// sized ints and float32 too, so that out of range values fall back to def instead of wrapping
+		return getValue(key, def)
 	}
 }
 
-const envListSeparator = ","
+// getValue parses any type supported by Parse, falling back to def on malformed values
+func getValue[T any](key string, def T) T {
+	v := def
+	rv := reflect.ValueOf(&v).Elem()
+	if !supported(rv.Type()) {
+		panic("Env: unsupported parameter type " + rv.Type().String())
+	}
+	raw, ok, _ := lookup(key)
+	if !ok || parseInto(rv, raw) != nil {
+		return def
+	}
+	return v
+}
 
 func String(key string, def string) string {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok || strings.TrimSpace(val) == "" {
 		return def
 	}
@@ -60,15 +71,21 @@ func String(key string, def string) string {
 }
 
 func Strings(key string, def []string) []string {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok || val == "" {
 		return def
 	}
-	return strings.Split(val, envListSeparator)
+	items := splitList(val, ListSeparator())
+	for i, item := range items {
+		items[i] = unescapeList(item, ListSeparator())
+	}
+	return items
 }
 
 func Int(key string, def int) int {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -80,7 +97,8 @@ func Int(key string, def int) int {
 }
 
 func Int64(key string, def int64) int64 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -92,7 +110,8 @@ func Int64(key string, def int64) int64 {
 }
 
 func Int32(key string, def int32) int32 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -104,7 +123,8 @@ func Int32(key string, def int32) int32 {
 }
 
 func Int16(key string, def int16) int16 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -116,7 +136,8 @@ func Int16(key string, def int16) int16 {
 }
 
 func Int8(key string, def int8) int8 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -128,7 +149,8 @@ func Int8(key string, def int8) int8 {
 }
 
 func Uint(key string, def uint) uint {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -140,7 +162,8 @@ func Uint(key string, def uint) uint {
 }
 
 func Uint64(key string, def uint64) uint64 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -152,7 +175,8 @@ func Uint64(key string, def uint64) uint64 {
 }
 
 func Uint32(key string, def uint32) uint32 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -164,7 +188,8 @@ func Uint32(key string, def uint32) uint32 {
 }
 
 func Uint16(key string, def uint16) uint16 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -176,7 +201,8 @@ func Uint16(key string, def uint16) uint16 {
 }
 
 func Uint8(key string, def uint8) uint8 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -188,11 +214,13 @@ func Uint8(key string, def uint8) uint8 {
 }
 
 func Uintptr(key string, def uintptr) uintptr {
+	register(key, def)
 	return uintptr(Uint64(key, uint64(def)))
 }
 
 func Float64(key string, def float64) float64 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -204,7 +232,8 @@ func Float64(key string, def float64) float64 {
 }
 
 func Float32(key string, def float32) float32 {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -216,15 +245,21 @@ func Float32(key string, def float32) float32 {
 }
 
 func Bool(key string, def bool) bool {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
-	return strings.ToLower(val) == "true"
+	v, err := parseBool(strings.TrimSpace(val))
+	if err != nil {
+		return def
+	}
+	return v
 }
 
 func Duration(key string, def time.Duration) time.Duration {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
@@ -236,7 +271,8 @@ func Duration(key string, def time.Duration) time.Duration {
 }
 
 func Time(key string, def time.Time) time.Time {
-	val, ok := os.LookupEnv(key)
+	register(key, def)
+	val, ok := Lookup(key)
 	if !ok {
 		return def
 	}
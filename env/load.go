@@ -0,0 +1,145 @@
+package env
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// Struct tags recognized by Load
+const (
+	TagKey      = "env"
+	TagDefault  = "default"
+	TagRequired = "required"
+	TagPrefix   = "prefix"
+	// TagSeparator overrides the list separator for a slice or map field
+	TagSeparator = "sep"
+)
+
+// ErrNotStructPtr is returned by Load when dst is not a non-nil pointer to a struct
+var ErrNotStructPtr = errors.New("env: Load expects a non-nil pointer to a struct")
+
+// Load fills the struct pointed by dst from the environment.
+//
+// Fields are described with tags:
+//
+//	type Config struct {
+//		DSN     string        `env:"PG_DSN" required:"true"`
+//		Timeout time.Duration `env:"PG_TIMEOUT" default:"5s" desc:"query timeout"`
+//		HTTP    HTTPConfig    `prefix:"HTTP_"`
+//	}
+//
+// Nested and embedded structs are walked recursively, the optional prefix tag is
+// prepended to every key below the field. Fields without a value and without
+// a default keep whatever they held before the call. Loaded fields are then checked
+// against their validate rules, see TagValidate. Every missing required key, unparsable
+// value and violated rule is reported in a single joined error.
+func Load(dst any) error {
+	return load(dst, nil)
+}
+
+// load fills dst, resolve maps the tagged keys to the keys actually looked up and may be nil
+func load(dst any, resolve func(key string) string) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotStructPtr
+	}
+	var errs Errors
+	loadStruct(rv.Elem(), "", resolve, &errs)
+	return errs.Err()
+}
+
+func loadStruct(rv reflect.Value, prefix string, resolve func(string) string, errs *Errors) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		if !field.IsExported() {
+			// exported fields of embedded unexported structs are still settable
+			if field.Anonymous && fv.Kind() == reflect.Struct {
+				loadStruct(fv, prefix+field.Tag.Get(TagPrefix), resolve, errs)
+			}
+			continue
+		}
+		key, hasKey := field.Tag.Lookup(TagKey)
+		if key == "-" {
+			continue
+		}
+		if !hasKey {
+			if nested, ok := nestedStruct(fv); ok {
+				loadStruct(nested, prefix+field.Tag.Get(TagPrefix), resolve, errs)
+			} else {
+				validateField(fv, field.Name, field.Tag, errs)
+			}
+			continue
+		}
+		key = prefix + key
+		if resolve != nil {
+			key = resolve(key)
+		}
+		loadField(fv, key, field.Tag, errs)
+	}
+}
+
+// nestedStruct returns the struct value to descend into, allocating nil struct pointers
+func nestedStruct(fv reflect.Value) (reflect.Value, bool) {
+	if !isNested(fv.Type()) {
+		return reflect.Value{}, false
+	}
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return fv.Elem(), true
+	}
+	return fv, true
+}
+
+func loadField(fv reflect.Value, key string, tag reflect.StructTag, errs *Errors) {
+	required, _ := parseBool(tag.Get(TagRequired))
+	registerVar(key, fv.Type(), RedactURL(tag.Get(TagDefault)), required, tag.Get(TagDescription))
+	raw, ok, err := lookup(key)
+	if err != nil {
+		errs.Add(err)
+		return
+	}
+	if !ok {
+		def, hasDef := tag.Lookup(TagDefault)
+		if !hasDef {
+			if required {
+				errs.Add(errMissing(key))
+				return
+			}
+			validateField(fv, key, tag, errs)
+			return
+		}
+		raw = def
+	}
+	sep := tag.Get(TagSeparator)
+	if sep == "" {
+		sep = ListSeparator()
+	}
+	if err := parseSep(fv, raw, sep); err != nil {
+		errs.Add(errInvalid(key, raw, fv.Type(), err))
+		return
+	}
+	validateField(fv, key, tag, errs)
+}
+
+var (
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+)
+
+// isNested reports whether t is a plain struct (or a pointer to one) holding further config fields
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	ptr := reflect.PointerTo(t)
+	return !ptr.Implements(textMarshalerType) && !ptr.Implements(jsonMarshalerType)
+}
@@ -0,0 +1,70 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+)
+
+type loadHTTP struct {
+	Port  int      `env:"PORT" default:"8080"`
+	Hosts []string `env:"HOSTS"`
+}
+
+type loadBase struct {
+	Debug bool `env:"LOAD_DEBUG" default:"false"`
+}
+
+type loadConfig struct {
+	loadBase
+	DSN     string        `env:"LOAD_DSN" required:"true"`
+	Timeout time.Duration `env:"LOAD_TIMEOUT" default:"5s"`
+	Workers uint8         `env:"LOAD_WORKERS"`
+	HTTP    loadHTTP      `prefix:"LOAD_HTTP_"`
+	Admin   *loadHTTP     `prefix:"LOAD_ADMIN_"`
+	Skipped string        `env:"-"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("LOAD_DSN", "postgres://localhost")
+	t.Setenv("LOAD_DEBUG", "true")
+	t.Setenv("LOAD_HTTP_HOSTS", "a,b")
+	t.Setenv("LOAD_ADMIN_PORT", "9090")
+
+	cfg := loadConfig{Workers: 4}
+	require.NoError(t, env.Load(&cfg))
+
+	assert.Equal(t, "postgres://localhost", cfg.DSN)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, uint8(4), cfg.Workers)
+	assert.Equal(t, 8080, cfg.HTTP.Port)
+	assert.Equal(t, []string{"a", "b"}, cfg.HTTP.Hosts)
+	require.NotNil(t, cfg.Admin)
+	assert.Equal(t, 9090, cfg.Admin.Port)
+}
+
+func TestLoad_AggregatedError(t *testing.T) {
+	t.Setenv("LOAD_TIMEOUT", "5 sec")
+	t.Setenv("LOAD_WORKERS", "300")
+
+	var cfg loadConfig
+	err := env.Load(&cfg)
+	require.Error(t, err)
+
+	assert.True(t, errors.Is(err, env.ErrMissing))
+	assert.True(t, errors.Is(err, env.ErrInvalid))
+	assert.Contains(t, err.Error(), "LOAD_DSN")
+	assert.Contains(t, err.Error(), "LOAD_TIMEOUT")
+	assert.Contains(t, err.Error(), "LOAD_WORKERS")
+}
+
+func TestLoad_NotStructPtr(t *testing.T) {
+	var cfg loadConfig
+	assert.ErrorIs(t, env.Load(cfg), env.ErrNotStructPtr)
+	assert.ErrorIs(t, env.Load((*loadConfig)(nil)), env.ErrNotStructPtr)
+}
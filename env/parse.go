@@ -0,0 +1,280 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+var (
+	// ErrMissing is returned when a required key is not set
+	ErrMissing = errors.New("missing value")
+	// ErrInvalid is returned when a value is present but can not be parsed into the target type
+	ErrInvalid = errors.New("invalid value")
+	// ErrUnsupported is returned when the target type is not supported by the parser
+	ErrUnsupported = errors.New("unsupported type")
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	urlType             = reflect.TypeFor[url.URL]()
+	bytesType           = reflect.TypeFor[[]byte]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// DefaultListSeparator separates the items of slices and maps
+const DefaultListSeparator = ","
+
+var listSeparator atomic.Pointer[string]
+
+// SetListSeparator changes the separator used for slices and maps, DefaultListSeparator by default.
+// Separators inside items are escaped with a backslash, e.g. "a\,b,c" is ["a,b" "c"].
+func SetListSeparator(sep string) {
+	listSeparator.Store(&sep)
+}
+
+// ListSeparator returns the separator used for slices and maps
+func ListSeparator() string {
+	if sep := listSeparator.Load(); sep != nil {
+		return *sep
+	}
+	return DefaultListSeparator
+}
+
+// parseInto parses raw into dst according to its type, using the current list separator
+func parseInto(dst reflect.Value, raw string) error {
+	return parseSep(dst, raw, ListSeparator())
+}
+
+// parseSep parses raw into dst, sep separates the items of slices and maps.
+// Supported are the basic kinds, time.Duration, time.Time (RFC3339), url.URL, slices,
+// maps ("k=v,k2=v2"), pointers to any of them and every encoding.TextUnmarshaler
+// such as net.IP, netip.Addr, netip.Prefix or ByteSize.
+func parseSep(dst reflect.Value, raw string, sep string) error {
+	typ := dst.Type()
+	if reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	switch typ {
+	case durationType:
+		v, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(v))
+		return nil
+	case timeType:
+		v, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	case urlType:
+		v, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(*v))
+		return nil
+	case bytesType:
+		dst.SetBytes([]byte(raw))
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(raw)
+	case reflect.Bool:
+		v, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, typ.Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := strconv.ParseUint(raw, 10, typ.Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, typ.Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(v)
+	case reflect.Pointer:
+		v := reflect.New(typ.Elem())
+		if err := parseSep(v.Elem(), raw, sep); err != nil {
+			return err
+		}
+		dst.Set(v)
+	case reflect.Slice:
+		items := splitList(raw, sep)
+		v := reflect.MakeSlice(typ, len(items), len(items))
+		for i, item := range items {
+			if err := parseSep(v.Index(i), unescapeList(item, sep), sep); err != nil {
+				return err
+			}
+		}
+		dst.Set(v)
+	case reflect.Map:
+		items := splitList(raw, sep)
+		v := reflect.MakeMapWithSize(typ, len(items))
+		for _, item := range items {
+			k, e, ok := cutUnescaped(item, "=")
+			if !ok {
+				return errors.New("expected key=value, got " + strconv.Quote(item))
+			}
+			key := reflect.New(typ.Key()).Elem()
+			if err := parseSep(key, unescapeList(strings.TrimSpace(k), sep, "="), sep); err != nil {
+				return err
+			}
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := parseSep(elem, unescapeList(strings.TrimSpace(e), sep, "="), sep); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+		dst.Set(v)
+	default:
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// supported reports whether parseSep is able to parse into typ
+func supported(typ reflect.Type) bool {
+	if reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch typ {
+	case durationType, timeType, urlType, bytesType:
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Pointer:
+		return supported(typ.Elem())
+	case reflect.Slice:
+		return scalar(typ.Elem())
+	case reflect.Map:
+		return scalar(typ.Key()) && scalar(typ.Elem())
+	}
+	return false
+}
+
+// scalar reports whether typ is supported and does not consume the list separator itself
+func scalar(typ reflect.Type) bool {
+	kind := typ.Kind()
+	if kind == reflect.Pointer {
+		typ = typ.Elem()
+		kind = typ.Kind()
+	}
+	if kind == reflect.Slice || kind == reflect.Map {
+		return typ == bytesType || reflect.PointerTo(typ).Implements(textUnmarshalerType)
+	}
+	return supported(typ)
+}
+
+// splitList splits raw by unescaped separators, trimming the still escaped items
+func splitList(raw, sep string) []string {
+	if raw == "" {
+		return []string{}
+	}
+	items := make([]string, 0, strings.Count(raw, sep)+1)
+	for {
+		item, rest, ok := cutUnescaped(raw, sep)
+		items = append(items, strings.TrimSpace(item))
+		if !ok {
+			return items
+		}
+		raw = rest
+	}
+}
+
+// cutUnescaped slices s around the first sep not preceded by a backslash
+func cutUnescaped(s, sep string) (string, string, bool) {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], sep):
+			return s[:i], s[i+len(sep):], true
+		}
+	}
+	return s, "", false
+}
+
+// unescapeList drops the backslashes escaping seps and backslashes themselves,
+// other backslashes are kept as is, so Windows paths survive
+func unescapeList(s string, seps ...string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && escapes(s[i+1:], seps) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func escapes(s string, seps []string) bool {
+	if s[0] == '\\' {
+		return true
+	}
+	for _, sep := range seps {
+		if strings.HasPrefix(s, sep) {
+			return true
+		}
+	}
+	return false
+}
+
+// errMissing builds an error for the required key that is not set
+func errMissing(key string) error {
+	return xerrors.Err(ErrMissing).
+		Str("key", key).
+		Send()
+}
+
+// errInvalid builds an error for the value that can not be parsed into typ
+func errInvalid(key, raw string, typ reflect.Type, cause error) error {
+	if errors.Is(cause, ErrUnsupported) {
+		return xerrors.Err(ErrUnsupported).
+			Str("key", key).
+			Str("type", typ.String()).
+			Send()
+	}
+	var numErr *strconv.NumError
+	if errors.As(cause, &numErr) {
+		cause = numErr.Err
+	}
+	return xerrors.Err(ErrInvalid).
+		Str("key", key).
+		Str("value", strconv.Quote(raw)).
+		Str("type", typ.String()).
+		Str("reason", strconv.Quote(cause.Error())).
+		Send()
+}
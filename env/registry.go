@@ -0,0 +1,228 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// TagDescription documents a field for the registry
+	TagDescription = "desc"
+	// HelpEnvFlag is the command line flag handled by HandleHelpEnv
+	HelpEnvFlag = "--help-env"
+)
+
+// Var describes a key read by the binary
+type Var struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+	Packages    []string `json:"packages"`
+}
+
+type registry struct {
+	mu   sync.Mutex
+	vars map[string]*Var
+}
+
+var (
+	vars = registry{
+		vars: make(map[string]*Var),
+	}
+	registryDisabled atomic.Bool
+)
+
+// SetRegistryEnabled turns the registration of read keys on or off, it is on by default
+func SetRegistryEnabled(enabled bool) {
+	registryDisabled.Store(!enabled)
+}
+
+// Describe attaches a human readable description to key
+func Describe(key, description string) {
+	vars.mu.Lock()
+	defer vars.mu.Unlock()
+	v := vars.get(key)
+	v.Description = description
+}
+
+// register records key with the type and default of def, along with the calling package.
+// Type and default of the first registration win, packages are accumulated.
+func register(key string, def any) {
+	registerVar(key, reflect.TypeOf(def), formatDefault(def), false, "")
+}
+
+func registerVar(key string, typ reflect.Type, def string, required bool, description string) {
+	if registryDisabled.Load() {
+		return
+	}
+	pkg := callerPackage()
+	vars.mu.Lock()
+	defer vars.mu.Unlock()
+	v := vars.get(key)
+	if v.Type == "" && typ != nil {
+		v.Type = typ.String()
+		v.Default = def
+	}
+	v.Required = v.Required || required
+	if description != "" {
+		v.Description = description
+	}
+	if pkg != "" && !slices.Contains(v.Packages, pkg) {
+		v.Packages = append(v.Packages, pkg)
+	}
+}
+
+func (r *registry) get(key string) *Var {
+	v, ok := r.vars[key]
+	if !ok {
+		v = &Var{Key: key}
+		r.vars[key] = v
+	}
+	return v
+}
+
+// Vars returns every registered key sorted by name
+func Vars() []Var {
+	vars.mu.Lock()
+	res := make([]Var, 0, len(vars.vars))
+	for _, v := range vars.vars {
+		c := *v
+		c.Packages = slices.Clone(v.Packages)
+		res = append(res, c)
+	}
+	vars.mu.Unlock()
+	for i := range res {
+		res[i].Secret = IsSecret(res[i].Key)
+		if res[i].Secret && res[i].Default != "" {
+			res[i].Default = Redacted
+		}
+	}
+	slices.SortFunc(res, func(a, b Var) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return res
+}
+
+// WriteJSON dumps the registry as a JSON array
+func WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Vars())
+}
+
+// WriteMarkdown dumps the registry as a Markdown table
+func WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Key | Type | Default | Required | Description | Packages |\n")
+	b.WriteString("|-----|------|---------|----------|-------------|----------|\n")
+	for _, v := range Vars() {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			v.Key,
+			v.Type,
+			markdownCode(v.Default),
+			yesNo(v.Required),
+			strings.ReplaceAll(v.Description, "|", `\|`),
+			strings.Join(v.Packages, ", "),
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHelp dumps the registry as a plain text table, as printed for --help-env
+func WriteHelp(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, v := range Vars() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", v.Key, v.Type, v.Default, yesNo(v.Required), v.Description)
+	}
+	return tw.Flush()
+}
+
+// HandleHelpEnv prints the registry and exits when args contain --help-env.
+// Call it after the keys were read, e.g. after fx.New and before app.Run.
+func HandleHelpEnv(args []string) {
+	if !slices.Contains(args, HelpEnvFlag) {
+		return
+	}
+	if err := WriteHelp(os.Stdout); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// callerPackage returns the import path of the first caller outside of this package
+func callerPackage() string {
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if pkg := funcPackage(frame.Function); pkg != "" && pkg != thisPackage {
+			return pkg
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+var thisPackage = reflect.TypeFor[Var]().PkgPath()
+
+// funcPackage extracts the import path from a qualified function name
+// like github.com/xakepp35/pkg/xpgx.NewModule.func1
+func funcPackage(name string) string {
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return name[:slash+1+dot]
+}
+
+func formatDefault(def any) string {
+	switch v := def.(type) {
+	case nil:
+		return ""
+	case string:
+		return RedactURL(v)
+	case []string:
+		return strings.Join(v, ListSeparator())
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(def)
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + s + "`"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
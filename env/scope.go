@@ -0,0 +1,118 @@
+package env
+
+import "time"
+
+// Scoped reads the config block of one instance of a multi-instance module.
+// A key resolves to PREFIX_KEY_NAME, falls back to PREFIX_KEY and then to the default,
+// so that every instance can share settings and override only what differs:
+//
+//	scope := env.Scope("PG", "replica")
+//	dsn := scope.String("DSN", DefaultDSN) // PG_DSN_REPLICA, then PG_DSN
+type Scoped struct {
+	prefix string
+	name   string
+}
+
+// Scope returns the reader of the name instance of the prefix module, name may be empty
+func Scope(prefix, name string) Scoped {
+	return Scoped{
+		prefix: dirKey(prefix),
+		name:   dirKey(name),
+	}
+}
+
+// Keys returns the keys consulted for key, the most specific first
+func (s Scoped) Keys(key string) []string {
+	if s.prefix != "" {
+		key = s.prefix + "_" + key
+	}
+	if s.name == "" {
+		return []string{key}
+	}
+	return []string{key + "_" + s.name, key}
+}
+
+// Key returns the most specific key for key
+func (s Scoped) Key(key string) string {
+	return s.Keys(key)[0]
+}
+
+// Resolve returns the first of Keys that is set, or the most specific one if none is
+func (s Scoped) Resolve(key string) string {
+	keys := s.Keys(key)
+	for _, k := range keys {
+		if _, ok, err := lookup(k); ok || err != nil {
+			return k
+		}
+	}
+	return keys[0]
+}
+
+// Lookup implements Source over the relative keys of the scope
+func (s Scoped) Lookup(key string) (string, bool) {
+	return Lookup(s.Resolve(key))
+}
+
+// Describe attaches a description to the most specific key
+func (s Scoped) Describe(key, description string) {
+	Describe(s.Key(key), description)
+}
+
+// MarkSecret marks every key consulted for keys as secret
+func (s Scoped) MarkSecret(keys ...string) {
+	for _, key := range keys {
+		MarkSecret(s.Keys(key)...)
+	}
+}
+
+// Load fills dst like Load does, resolving the env tags as keys of the scope
+func (s Scoped) Load(dst any) error {
+	return load(dst, s.Resolve)
+}
+
+// Redact is Redact for a config loaded with Load of the scope, checking the keys the fields were resolved to
+func (s Scoped) Redact(cfg any) any {
+	return redact(cfg, s.Resolve)
+}
+
+func (s Scoped) String(key string, def string) string {
+	return String(s.Resolve(key), def)
+}
+
+func (s Scoped) Strings(key string, def []string) []string {
+	return Strings(s.Resolve(key), def)
+}
+
+func (s Scoped) Int(key string, def int) int {
+	return Int(s.Resolve(key), def)
+}
+
+func (s Scoped) Int64(key string, def int64) int64 {
+	return Int64(s.Resolve(key), def)
+}
+
+func (s Scoped) Uint(key string, def uint) uint {
+	return Uint(s.Resolve(key), def)
+}
+
+func (s Scoped) Float64(key string, def float64) float64 {
+	return Float64(s.Resolve(key), def)
+}
+
+func (s Scoped) Bool(key string, def bool) bool {
+	return Bool(s.Resolve(key), def)
+}
+
+func (s Scoped) Duration(key string, def time.Duration) time.Duration {
+	return Duration(s.Resolve(key), def)
+}
+
+// GetScoped is Get for the keys of a scope
+func GetScoped[T any](s Scoped, key string, def T) T {
+	return Get(s.Resolve(key), def)
+}
+
+// ParseScoped is Parse for the keys of a scope
+func ParseScoped[T any](s Scoped, key string) (T, error) {
+	return Parse[T](s.Resolve(key))
+}
@@ -0,0 +1,58 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+)
+
+func TestScope(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{
+		"SCOPE_DSN":           "postgres://primary",
+		"SCOPE_DSN_REPLICA":   "postgres://replica",
+		"SCOPE_TIMEOUT":       "3s",
+		"SCOPE_POOL_MAX_NEXT": "7",
+	}))
+
+	replica := env.Scope("SCOPE", "replica")
+	assert.Equal(t, []string{"SCOPE_DSN_REPLICA", "SCOPE_DSN"}, replica.Keys("DSN"))
+	assert.Equal(t, "postgres://replica", replica.String("DSN", ""))
+	assert.Equal(t, 3*time.Second, replica.Duration("TIMEOUT", time.Second))
+	assert.Equal(t, 10, replica.Int("SIZE", 10))
+
+	primary := env.Scope("SCOPE", "")
+	assert.Equal(t, []string{"SCOPE_DSN"}, primary.Keys("DSN"))
+	assert.Equal(t, "postgres://primary", primary.String("DSN", ""))
+	assert.Equal(t, "postgres://primary", env.GetScoped(env.Scope("SCOPE", "other"), "DSN", ""))
+
+	var cfg struct {
+		DSN     string        `env:"DSN" required:"true"`
+		Timeout time.Duration `env:"TIMEOUT"`
+		Pool    struct {
+			Max int `env:"MAX" default:"1"`
+		} `prefix:"POOL_"`
+	}
+	require.NoError(t, env.Scope("SCOPE", "next").Load(&cfg))
+	assert.Equal(t, "postgres://primary", cfg.DSN)
+	assert.Equal(t, 3*time.Second, cfg.Timeout)
+	assert.Equal(t, 7, cfg.Pool.Max)
+}
+
+func TestScope_Redact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("supersecret"), 0o600))
+	defer env.SetSource(env.SetSource(env.Map{"SCOPE_TOKEN_REPLICA_FILE": path}))
+
+	var cfg struct {
+		Token string `env:"TOKEN"`
+	}
+	scope := env.Scope("SCOPE", "replica")
+	require.NoError(t, scope.Load(&cfg))
+	assert.Equal(t, "supersecret", cfg.Token)
+	assert.Equal(t, map[string]any{"Token": env.Redacted}, scope.Redact(&cfg))
+}
@@ -0,0 +1,213 @@
+package env
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+const (
+	// FileSuffix marks a key whose value is the path of a file holding the actual value,
+	// e.g. PG_DSN_FILE=/run/secrets/pg_dsn is used when PG_DSN is unset
+	FileSuffix = "_FILE"
+	// TagSecret marks a struct field as secret for Redact
+	TagSecret = "secret"
+	// Redacted replaces secret values in logged config
+	Redacted = "******"
+)
+
+var (
+	secrets sync.Map
+	// secretFiles holds the paths named by KEY_FILE keys, which Watcher polls
+	secretFiles sync.Map
+)
+
+// MarkSecret marks keys as secret, so that Redact hides the fields loaded from them
+func MarkSecret(keys ...string) {
+	for _, key := range keys {
+		secrets.Store(key, struct{}{})
+	}
+}
+
+// IsSecret reports whether key was marked as secret or resolved from a secret file
+func IsSecret(key string) bool {
+	_, ok := secrets.Load(key)
+	return ok
+}
+
+// resolve looks key up in the current source, falling back to the file named by KEY_FILE
+func resolve(key string) (string, bool, error) {
+	src := CurrentSource()
+	if val, ok := src.Lookup(key); ok {
+		return val, true, nil
+	}
+	path, ok := src.Lookup(key + FileSuffix)
+	if !ok || strings.TrimSpace(path) == "" {
+		return "", false, nil
+	}
+	path = strings.TrimSpace(path)
+	secretFiles.Store(path, struct{}{})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, xerrors.Err(err).
+			Str("key", key+FileSuffix).
+			Send()
+	}
+	MarkSecret(key)
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+// DirSource maps the files of a directory to keys, as Docker and Kubernetes mount secrets.
+// File names are upper cased with '-' and '.' replaced by '_', so pg-dsn becomes PG_DSN.
+// Every key of the source is marked as secret.
+type DirSource struct {
+	dir    string
+	mu     sync.RWMutex
+	values Map
+	stamps []fileStamp
+}
+
+// Dir reads every regular file of dir into a source
+func Dir(dir string) (*DirSource, error) {
+	s := &DirSource{
+		dir: dir,
+	}
+	s.stamps = s.stamp()
+	values, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.values = values
+	return s, nil
+}
+
+func (s *DirSource) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values.Lookup(key)
+}
+
+func (s *DirSource) read() (Map, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	values := make(Map, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(s.dir, name)
+		// stat follows the symlinks Kubernetes uses for mounted secrets
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, xerrors.Err(err).Str("path", path).Send()
+		}
+		key := dirKey(name)
+		values[key] = strings.TrimSpace(string(data))
+		MarkSecret(key)
+	}
+	return values, nil
+}
+
+var dirKeyReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+func dirKey(name string) string {
+	return strings.ToUpper(dirKeyReplacer.Replace(name))
+}
+
+// Redact returns a copy of cfg suitable for logging: structs become maps keyed like
+// encoding/json would, fields tagged secret:"true" or loaded from secret keys are replaced
+// by Redacted and passwords are stripped from URL valued strings. Other values are returned as is.
+// Configs loaded through a Scope are redacted with Scoped.Redact, which knows the keys they were resolved to.
+func Redact(cfg any) any {
+	return redact(cfg, nil)
+}
+
+func redact(cfg any, resolve func(key string) string) any {
+	rv := reflect.ValueOf(cfg)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return cfg
+	}
+	out := make(map[string]any, rv.NumField())
+	redactStruct(rv, "", resolve, out)
+	return out
+}
+
+func redactStruct(rv reflect.Value, prefix string, resolve func(string) string, out map[string]any) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			redactStruct(fv, prefix+field.Tag.Get(TagPrefix), resolve, out)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		key, hasKey := field.Tag.Lookup(TagKey)
+		key = prefix + key
+		if resolve != nil && hasKey {
+			key = resolve(key)
+		}
+		secret, _ := parseBool(field.Tag.Get(TagSecret))
+		switch {
+		case secret || hasKey && IsSecret(key):
+			out[name] = Redacted
+		case !hasKey && isNested(fv.Type()):
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					out[name] = nil
+					continue
+				}
+				fv = fv.Elem()
+			}
+			nested := make(map[string]any, fv.NumField())
+			redactStruct(fv, prefix+field.Tag.Get(TagPrefix), resolve, nested)
+			out[name] = nested
+		case fv.Kind() == reflect.String:
+			out[name] = RedactURL(fv.String())
+		default:
+			out[name] = fv.Interface()
+		}
+	}
+}
+
+// RedactURL hides the password of URL like values such as DSNs, other strings are returned as is
+func RedactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), Redacted)
+	// url escapes the asterisks of the placeholder, restore them for readability
+	return strings.Replace(u.String(), url.QueryEscape(Redacted), Redacted, 1)
+}
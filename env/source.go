@@ -0,0 +1,107 @@
+package env
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Source looks up raw values by key
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// SourceFunc adapts an ordinary function to the Source interface
+type SourceFunc func(key string) (string, bool)
+
+func (f SourceFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
+
+// OS returns the source reading the process environment
+func OS() Source {
+	return SourceFunc(os.LookupEnv)
+}
+
+// Map is an in-memory source, handy for hermetic tests
+type Map map[string]string
+
+func (m Map) Lookup(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+type chain []Source
+
+// Chain returns a source that consults sources in order and returns the first hit,
+// so the first source has the highest precedence
+func Chain(sources ...Source) Source {
+	return chain(sources)
+}
+
+func (c chain) Lookup(key string) (string, bool) {
+	for _, s := range c {
+		if v, ok := s.Lookup(key); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+type sourceHolder struct {
+	Source
+}
+
+var current atomic.Pointer[sourceHolder]
+
+func init() {
+	current.Store(&sourceHolder{OS()})
+}
+
+// SetSource replaces the source used by every lookup of the package and returns the previous one
+//
+//	defer env.SetSource(env.SetSource(env.Map{"PORT": "9090"}))
+func SetSource(s Source) Source {
+	return current.Swap(&sourceHolder{s}).Source
+}
+
+// CurrentSource returns the source used by every lookup of the package
+func CurrentSource() Source {
+	return current.Load().Source
+}
+
+// LoadDotenv layers the given dotenv files under the process environment,
+// resulting in the precedence defaults < files < OS env.
+// Later files override earlier ones, missing files are skipped until they are created.
+// The returned source can be passed to NewWatcher to reload the files on change.
+func LoadDotenv(paths ...string) (*FileSource, error) {
+	files := &FileSource{
+		paths:    paths,
+		optional: true,
+		stamps:   stampFiles(paths),
+	}
+	values, err := files.read()
+	if err != nil {
+		return nil, err
+	}
+	files.values = values
+	SetSource(Chain(OS(), files))
+	return files, nil
+}
+
+// Lookup returns the raw value of key from the current source,
+// falling back to the contents of the file named by KEY_FILE
+func Lookup(key string) (string, bool) {
+	val, ok, _ := resolve(key)
+	return val, ok
+}
+
+// lookup returns the trimmed value of key, treating blank values as unset
+func lookup(key string) (string, bool, error) {
+	val, ok, err := resolve(key)
+	if !ok {
+		return "", false, err
+	}
+	val = strings.TrimSpace(val)
+	return val, val != "", nil
+}
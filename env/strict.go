@@ -0,0 +1,102 @@
+package env
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Parse returns the value of key parsed as T, or ErrMissing if it is not set.
+// Unlike Get it never falls back silently, malformed values are reported as ErrInvalid.
+func Parse[T any](key string) (T, error) {
+	var v T
+	registerVar(key, reflect.TypeFor[T](), "", true, "")
+	raw, ok, err := lookup(key)
+	if err != nil {
+		return v, err
+	}
+	if !ok {
+		return v, errMissing(key)
+	}
+	return v, parseValue(&v, key, raw)
+}
+
+// ParseOr returns the value of key parsed as T, or def if it is not set.
+// Malformed values are reported as ErrInvalid together with def.
+func ParseOr[T any](key string, def T) (T, error) {
+	register(key, def)
+	raw, ok, err := lookup(key)
+	if !ok {
+		return def, err
+	}
+	v := def
+	if err := parseValue(&v, key, raw); err != nil {
+		return def, err
+	}
+	return v, nil
+}
+
+func parseValue(ptr any, key, raw string) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	if err := parseInto(rv, raw); err != nil {
+		return errInvalid(key, raw, rv.Type(), err)
+	}
+	return nil
+}
+
+// Errors collects parsing errors, so that a whole config block can be reported at once
+//
+//	var errs env.Errors
+//	dsn := env.Require[string](&errs, "PG_DSN")
+//	timeout := env.Collect(&errs, "PG_TIMEOUT", 5*time.Second)
+//	if err := errs.Err(); err != nil {
+//		return err
+//	}
+type Errors struct {
+	errs []error
+}
+
+// Add appends err to the collection, nil errors are ignored
+func (e *Errors) Add(err error) {
+	if err != nil {
+		e.errs = append(e.errs, err)
+	}
+}
+
+// Len returns the number of collected errors
+func (e *Errors) Len() int {
+	return len(e.errs)
+}
+
+// Err returns all collected errors joined together, or nil if there are none
+func (e *Errors) Err() error {
+	return errors.Join(e.errs...)
+}
+
+// Require returns the value of key parsed as T, collecting an error if it is missing or malformed
+func Require[T any](errs *Errors, key string) T {
+	v, err := Parse[T](key)
+	errs.Add(err)
+	return v
+}
+
+// Collect returns the value of key parsed as T or def if it is not set, collecting an error if it is malformed
+func Collect[T any](errs *Errors, key string, def T) T {
+	v, err := ParseOr(key, def)
+	errs.Add(err)
+	return v
+}
+
+// ErrBool is the cause reported for values that are not one of the known bool spellings
+var ErrBool = errors.New("expected one of true/false, 1/0, yes/no, on/off")
+
+// parseBool accepts the usual bool spellings: true/false, t/f, 1/0, yes/no, y/n, on/off
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "true", "t", "1", "yes", "y", "on":
+		return true, nil
+	case "false", "f", "0", "no", "n", "off":
+		return false, nil
+	}
+	return false, ErrBool
+}
@@ -0,0 +1,63 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+)
+
+func TestParse(t *testing.T) {
+	t.Setenv("STRICT_TIMEOUT", "5 sec")
+	t.Setenv("STRICT_PORT", "8080")
+
+	port, err := env.Parse[int]("STRICT_PORT")
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	_, err = env.Parse[int]("STRICT_MISSING")
+	assert.ErrorIs(t, err, env.ErrMissing)
+
+	timeout, err := env.ParseOr("STRICT_TIMEOUT", time.Second)
+	assert.ErrorIs(t, err, env.ErrInvalid)
+	assert.Contains(t, err.Error(), `key=STRICT_TIMEOUT value="5 sec" type=time.Duration`)
+	assert.Equal(t, time.Second, timeout)
+}
+
+func TestParse_Bool(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"1": true, "yes": true, "ON": true, "True": true,
+		"0": false, "no": false, "off": false, "FALSE": false,
+	} {
+		t.Setenv("STRICT_BOOL", raw)
+		have, err := env.Parse[bool]("STRICT_BOOL")
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, have, raw)
+		assert.Equal(t, want, env.Bool("STRICT_BOOL", !want), raw)
+	}
+
+	t.Setenv("STRICT_BOOL", "maybe")
+	_, err := env.Parse[bool]("STRICT_BOOL")
+	assert.ErrorIs(t, err, env.ErrInvalid)
+	assert.True(t, env.Bool("STRICT_BOOL", true))
+}
+
+func TestErrors(t *testing.T) {
+	t.Setenv("STRICT_WORKERS", "many")
+
+	var errs env.Errors
+	dsn := env.Require[string](&errs, "STRICT_DSN")
+	workers := env.Collect(&errs, "STRICT_WORKERS", 4)
+	debug := env.Collect(&errs, "STRICT_DEBUG", true)
+
+	assert.Empty(t, dsn)
+	assert.Equal(t, 4, workers)
+	assert.True(t, debug)
+	assert.Equal(t, 2, errs.Len())
+	err := errs.Err()
+	assert.True(t, errors.Is(err, env.ErrMissing))
+	assert.True(t, errors.Is(err, env.ErrInvalid))
+}
@@ -0,0 +1,86 @@
+package env_test
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+)
+
+func TestGet_Types(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{
+		"TYPES_TIME":      "2025-01-02T03:04:05Z",
+		"TYPES_URL":       "https://example.com/path?q=1",
+		"TYPES_IP":        "10.0.0.1",
+		"TYPES_PREFIX":    "10.0.0.0/8",
+		"TYPES_SIZE":      "64MiB",
+		"TYPES_INTS":      "1, 2,3",
+		"TYPES_DURATIONS": "1s,1m",
+		"TYPES_MAP":       "env=prod, team = core,expr=a\\=b",
+		"TYPES_ESCAPED":   `a\,b,c,C:\dir`,
+		"TYPES_BROKEN":    "1,x",
+	}))
+
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), env.Get("TYPES_TIME", time.Time{}))
+	assert.Equal(t, "example.com", env.Get[*url.URL]("TYPES_URL", nil).Host)
+	assert.Equal(t, net.IPv4(10, 0, 0, 1).String(), env.Get[net.IP]("TYPES_IP", nil).String())
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), env.Get("TYPES_PREFIX", netip.Prefix{}))
+	assert.Equal(t, 64*env.MiB, env.Get("TYPES_SIZE", env.ByteSize(0)))
+	assert.Equal(t, []int{1, 2, 3}, env.Get("TYPES_INTS", []int(nil)))
+	assert.Equal(t, []time.Duration{time.Second, time.Minute}, env.Get("TYPES_DURATIONS", []time.Duration(nil)))
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core", "expr": "a=b"}, env.Get("TYPES_MAP", map[string]string(nil)))
+	assert.Equal(t, []string{"a,b", "c", `C:\dir`}, env.Strings("TYPES_ESCAPED", nil))
+	assert.Equal(t, []int{7}, env.Get("TYPES_BROKEN", []int{7}))
+	assert.Panics(t, func() { env.Get("TYPES_CHAN", make(chan int)) })
+}
+
+func TestGet_BitSize(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{
+		"TYPES_INT8":    "300",
+		"TYPES_INT16":   "-40000",
+		"TYPES_INT32":   "12",
+		"TYPES_FLOAT32": "1e40",
+	}))
+
+	assert.Equal(t, int8(1), env.Get("TYPES_INT8", int8(1)))
+	assert.Equal(t, int16(2), env.Get("TYPES_INT16", int16(2)))
+	assert.Equal(t, int32(12), env.Get("TYPES_INT32", int32(3)))
+	assert.Equal(t, float32(4), env.Get("TYPES_FLOAT32", float32(4)))
+}
+
+func TestLoad_Separator(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{"TYPES_PATHS": "/a;/b"}))
+
+	var cfg struct {
+		Paths []string     `env:"TYPES_PATHS" sep:";"`
+		Limit env.ByteSize `env:"TYPES_LIMIT" default:"1.5KB"`
+	}
+	require.NoError(t, env.Load(&cfg))
+	assert.Equal(t, []string{"/a", "/b"}, cfg.Paths)
+	assert.Equal(t, env.ByteSize(1500), cfg.Limit)
+}
+
+func TestParseByteSize(t *testing.T) {
+	for raw, want := range map[string]env.ByteSize{
+		"512":   512,
+		"10k":   10 * env.KB,
+		"64MiB": 64 * env.MiB,
+		"1.5GB": 1500 * env.MB,
+		"2 Gi":  2 * env.GiB,
+	} {
+		have, err := env.ParseByteSize(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, have, raw)
+	}
+	for _, raw := range []string{"", "MiB", "12XB", "1..5MB"} {
+		_, err := env.ParseByteSize(raw)
+		assert.Error(t, err, raw)
+	}
+	assert.Equal(t, "64MiB", (64 * env.MiB).String())
+	assert.Equal(t, "1500B", env.ByteSize(1500).String())
+}
@@ -0,0 +1,233 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// TagValidate lists the rules a field must satisfy, separated by commas:
+//
+//	Port    int           `env:"PORT" validate:"min=1,max=65535"`
+//	Level   string        `env:"LOG_LEVEL" validate:"oneof=debug info warn error"`
+//	Timeout time.Duration `env:"TIMEOUT" validate:"min=1s"`
+//	Addr    string        `env:"ADDR" validate:"nonzero,hostport"`
+//	Name    string        `env:"NAME" validate:"regex=^[a-z]+(,[a-z]+)*$"`
+//
+// min and max bound numbers and durations, or the length of strings, slices and maps.
+// regex consumes the rest of the tag, so it has to be the last rule.
+const TagValidate = "validate"
+
+var (
+	// ErrValidation is returned for values violating a validation rule
+	ErrValidation = errors.New("validation failed")
+	// ErrRule is returned for unknown or malformed validation rules
+	ErrRule = errors.New("invalid validation rule")
+)
+
+// Validate checks the validate tags of the struct pointed by cfg, reporting every violation at once
+func Validate(cfg any) error {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrNotStructPtr
+	}
+	var errs Errors
+	validateStruct(rv, "", &errs)
+	return errs.Err()
+}
+
+func validateStruct(rv reflect.Value, prefix string, errs *Errors) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		key, hasKey := field.Tag.Lookup(TagKey)
+		if !hasKey && isNested(field.Type) && (field.IsExported() || field.Anonymous) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			validateStruct(fv, prefix+field.Tag.Get(TagPrefix), errs)
+			continue
+		}
+		if !field.IsExported() || key == "-" {
+			continue
+		}
+		if !hasKey {
+			key = field.Name
+		} else {
+			key = prefix + key
+		}
+		validateField(fv, key, field.Tag, errs)
+	}
+}
+
+func validateField(fv reflect.Value, key string, tag reflect.StructTag, errs *Errors) {
+	rules := tag.Get(TagValidate)
+	for rules != "" {
+		var rule string
+		if strings.HasPrefix(rules, "regex=") {
+			rule, rules = rules, ""
+		} else {
+			rule, rules, _ = strings.Cut(rules, ",")
+		}
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		ok, err := checkRule(fv, name, arg)
+		switch {
+		case err != nil:
+			errs.Add(xerrors.Err(ErrRule).
+				Str("key", key).
+				Str("rule", rule).
+				Str("reason", strconv.Quote(err.Error())).
+				Send())
+		case !ok:
+			secret, _ := parseBool(tag.Get(TagSecret))
+			errs.Add(xerrors.Err(ErrValidation).
+				Str("key", key).
+				Str("rule", rule).
+				Str("value", formatValue(fv, secret || IsSecret(key))).
+				Send())
+		}
+	}
+}
+
+func formatValue(fv reflect.Value, secret bool) string {
+	if secret {
+		return Redacted
+	}
+	if fv.Kind() == reflect.Pointer && fv.IsNil() {
+		return "nil"
+	}
+	return strconv.Quote(fmt.Sprint(fv.Interface()))
+}
+
+func checkRule(fv reflect.Value, name, arg string) (bool, error) {
+	switch name {
+	case "nonzero":
+		return !fv.IsZero(), nil
+	case "min", "max":
+		cmp, err := compare(fv, arg)
+		if err != nil {
+			return false, err
+		}
+		if name == "min" {
+			return cmp >= 0, nil
+		}
+		return cmp <= 0, nil
+	case "oneof":
+		for _, option := range strings.Fields(arg) {
+			v := reflect.New(fv.Type()).Elem()
+			if err := parseInto(v, option); err != nil {
+				return false, err
+			}
+			if reflect.DeepEqual(v.Interface(), fv.Interface()) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "regex":
+		re, err := compileRegex(arg)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(stringOf(fv)), nil
+	case "url":
+		u, err := url.Parse(stringOf(fv))
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != ""), nil
+	case "hostport":
+		_, port, err := net.SplitHostPort(stringOf(fv))
+		if err != nil {
+			return false, nil
+		}
+		n, err := strconv.ParseUint(port, 10, 16)
+		return err == nil && n > 0, nil
+	}
+	return false, errors.New("unknown rule")
+}
+
+// compare returns the sign of fv minus bound, comparing lengths for strings, slices and maps
+func compare(fv reflect.Value, bound string) (int, error) {
+	switch fv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		n, err := strconv.Atoi(bound)
+		if err != nil {
+			return 0, err
+		}
+		return sign(float64(fv.Len() - n)), nil
+	}
+	b := reflect.New(fv.Type()).Elem()
+	if err := parseInto(b, bound); err != nil {
+		return 0, err
+	}
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return sign(float64(fv.Int() - b.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch {
+		case fv.Uint() > b.Uint():
+			return 1, nil
+		case fv.Uint() < b.Uint():
+			return -1, nil
+		}
+		return 0, nil
+	case reflect.Float32, reflect.Float64:
+		return sign(fv.Float() - b.Float()), nil
+	}
+	return 0, errors.New("min and max need a number, duration, string, slice or map")
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	}
+	return 0
+}
+
+// stringOf returns the text form of string and fmt.Stringer values like *url.URL
+func stringOf(fv reflect.Value) string {
+	if fv.Kind() == reflect.String {
+		return fv.String()
+	}
+	if fv.Kind() == reflect.Pointer && fv.IsNil() {
+		return ""
+	}
+	if s, ok := fv.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	if fv.CanAddr() {
+		if s, ok := fv.Addr().Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	return fmt.Sprint(fv.Interface())
+}
+
+var regexCache sync.Map
+
+func compileRegex(expr string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(expr, re)
+	return re, nil
+}
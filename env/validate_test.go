@@ -0,0 +1,77 @@
+package env_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+)
+
+type validateConfig struct {
+	Port     int           `env:"VALIDATE_PORT" default:"8080" validate:"min=1,max=65535"`
+	Level    string        `env:"VALIDATE_LEVEL" default:"info" validate:"oneof=debug info warn error"`
+	Timeout  time.Duration `env:"VALIDATE_TIMEOUT" default:"5s" validate:"min=1s,max=1m"`
+	Addr     string        `env:"VALIDATE_ADDR" default:":8080" validate:"nonzero,hostport"`
+	Hosts    []string      `env:"VALIDATE_HOSTS" default:"a" validate:"min=1"`
+	Callback *url.URL      `env:"VALIDATE_CALLBACK" validate:"url"`
+	Password string        `env:"VALIDATE_PASSWORD" secret:"true" validate:"min=8"`
+	Name     string        `env:"VALIDATE_NAME" default:"a,b" validate:"regex=^[a-z]+(,[a-z]+)*$"`
+}
+
+func TestLoad_Validate(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{
+		"VALIDATE_CALLBACK": "https://example.com/hook",
+		"VALIDATE_PASSWORD": "long enough",
+	}))
+
+	var cfg validateConfig
+	require.NoError(t, env.Load(&cfg))
+}
+
+func TestLoad_ValidateReport(t *testing.T) {
+	defer env.SetSource(env.SetSource(env.Map{
+		"VALIDATE_PORT":     "0",
+		"VALIDATE_LEVEL":    "trace",
+		"VALIDATE_TIMEOUT":  "2m",
+		"VALIDATE_ADDR":     "localhost",
+		"VALIDATE_HOSTS":    "",
+		"VALIDATE_CALLBACK": "/relative",
+		"VALIDATE_PASSWORD": "short",
+		"VALIDATE_NAME":     "A",
+	}))
+
+	var cfg validateConfig
+	err := env.Load(&cfg)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, env.ErrValidation))
+	for _, want := range []string{
+		`key=VALIDATE_PORT rule=min=1 value="0"`,
+		`key=VALIDATE_LEVEL rule=oneof=debug info warn error value="trace"`,
+		`key=VALIDATE_TIMEOUT rule=max=1m value="2m0s"`,
+		`key=VALIDATE_ADDR rule=hostport value="localhost"`,
+		`key=VALIDATE_CALLBACK rule=url`,
+		`key=VALIDATE_PASSWORD rule=min=8 value=` + env.Redacted,
+		`key=VALIDATE_NAME rule=regex=^[a-z]+(,[a-z]+)*$ value="A"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+	assert.NotContains(t, err.Error(), "VALIDATE_HOSTS")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := struct {
+		Addr  string  `validate:"hostport"`
+		Ratio float64 `validate:"max=1"`
+		Bad   int     `validate:"between=1"`
+	}{Addr: "::8080", Ratio: 0.5}
+
+	err := env.Validate(&cfg)
+	assert.ErrorIs(t, err, env.ErrValidation)
+	assert.ErrorIs(t, err, env.ErrRule)
+	assert.Contains(t, err.Error(), "key=Addr rule=hostport")
+	assert.NotContains(t, err.Error(), "Ratio")
+}
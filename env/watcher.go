@@ -0,0 +1,305 @@
+package env
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/xakepp35/pkg/xlog"
+)
+
+// Reloader is a source backed by files that can be re-read
+type Reloader interface {
+	Source
+	// Modified reports whether the files changed since they were last read
+	Modified() bool
+	Reload() error
+}
+
+// fileStamp identifies a version of a file by its size and modification time
+type fileStamp struct {
+	path  string
+	size  int64
+	mtime time.Time
+}
+
+// stampFiles returns the stamps of the regular files among paths, following symlinks
+func stampFiles(paths []string) []fileStamp {
+	stamps := make([]fileStamp, 0, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			stamps = append(stamps, fileStamp{path, info.Size(), info.ModTime()})
+		}
+	}
+	return stamps
+}
+
+// stampSecretFiles returns the stamps of the files named by KEY_FILE keys resolved so far
+func stampSecretFiles() []fileStamp {
+	var paths []string
+	secretFiles.Range(func(path, _ any) bool {
+		paths = append(paths, path.(string))
+		return true
+	})
+	slices.Sort(paths)
+	return stampFiles(paths)
+}
+
+// Modified reports whether any of the dotenv files changed since they were last read
+func (s *FileSource) Modified() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !slices.Equal(s.stamps, stampFiles(s.paths))
+}
+
+// Reload re-reads the dotenv files, keeping the previous values on failure
+func (s *FileSource) Reload() error {
+	stamps := stampFiles(s.paths)
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.values, s.stamps = values, stamps
+	s.mu.Unlock()
+	return nil
+}
+
+// Modified reports whether files of the directory were added, removed or changed since it was last read
+func (s *DirSource) Modified() bool {
+	stamps := s.stamp()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !slices.Equal(s.stamps, stamps)
+}
+
+// Reload re-reads the directory, keeping the previous values on failure
+func (s *DirSource) Reload() error {
+	stamps := s.stamp()
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.values, s.stamps = values, stamps
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *DirSource) stamp() []fileStamp {
+	entries, _ := os.ReadDir(s.dir)
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			paths = append(paths, filepath.Join(s.dir, entry.Name()))
+		}
+	}
+	return stampFiles(paths)
+}
+
+// Change describes a config field whose value changed on reload
+type Change struct {
+	Key   string
+	Field string
+	Old   any
+	New   any
+}
+
+// Watcher keeps a config struct loaded with Load up to date.
+// On SIGHUP, and every interval when one of the sources or of the files named by KEY_FILE keys was modified, it reloads the sources, loads a fresh T and,
+// when something changed, notifies the subscribers with the old and new values.
+// A reload failing to load keeps the previous config.
+type Watcher[T any] struct {
+	sources  []Reloader
+	interval time.Duration
+	current  atomic.Pointer[T]
+	reload   sync.Mutex
+	secrets  []fileStamp // guarded by reload
+
+	mu     sync.Mutex
+	subs   []func(old, new *T, changes []Change)
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWatcher loads the initial T, interval of zero disables polling and leaves SIGHUP only
+func NewWatcher[T any](interval time.Duration, sources ...Reloader) (*Watcher[T], error) {
+	cfg := new(T)
+	if err := Load(cfg); err != nil {
+		return nil, err
+	}
+	w := &Watcher[T]{
+		sources:  sources,
+		interval: interval,
+		secrets:  stampSecretFiles(),
+	}
+	w.current.Store(cfg)
+	return w, nil
+}
+
+// Get returns the current config, it must not be modified
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called after every reload that changed the config
+func (w *Watcher[T]) Subscribe(fn func(old, new *T, changes []Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Reload re-reads the sources and the config, notifying subscribers about the changes
+func (w *Watcher[T]) Reload() ([]Change, error) {
+	w.reload.Lock()
+	defer w.reload.Unlock()
+	for _, s := range w.sources {
+		if err := s.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	secrets := stampSecretFiles()
+	cfg := new(T)
+	if err := Load(cfg); err != nil {
+		return nil, err
+	}
+	w.secrets = secrets
+	old := w.current.Load()
+	changes := diffStruct(reflect.ValueOf(old).Elem(), reflect.ValueOf(cfg).Elem(), "", "", nil)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	w.current.Store(cfg)
+	w.mu.Lock()
+	subs := w.subs
+	w.mu.Unlock()
+	for _, fn := range subs {
+		fn(old, cfg, changes)
+	}
+	return changes, nil
+}
+
+// Start runs the watching loop until Stop
+func (w *Watcher[T]) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.mu.Lock()
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	w.mu.Unlock()
+	go w.run(ctx, w.done)
+	return nil
+}
+
+// Stop ends the watching loop and waits for it to return
+func (w *Watcher[T]) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Register binds the watching loop to the fx lifecycle
+func (w *Watcher[T]) Register(lc fx.Lifecycle) {
+	lc.Append(fx.Hook{
+		OnStart: w.Start,
+		OnStop:  w.Stop,
+	})
+}
+
+func (w *Watcher[T]) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-tick:
+			if !w.modified() {
+				continue
+			}
+		}
+		changes, err := w.Reload()
+		xlog.ErrDebug(err).
+			Int("changes", len(changes)).
+			Msg("env.Watcher.Reload")
+	}
+}
+
+// modified reports whether any of the sources or secret files changed since the last reload
+func (w *Watcher[T]) modified() bool {
+	for _, s := range w.sources {
+		if s.Modified() {
+			return true
+		}
+	}
+	w.reload.Lock()
+	defer w.reload.Unlock()
+	return !slices.Equal(w.secrets, stampSecretFiles())
+}
+
+// diffStruct compares the fields Load fills, following the same nesting and prefix rules
+func diffStruct(old, cur reflect.Value, prefix, path string, changes []Change) []Change {
+	rt := old.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		ov, cv := old.Field(i), cur.Field(i)
+		key, hasKey := field.Tag.Lookup(TagKey)
+		if key == "-" {
+			continue
+		}
+		if !hasKey {
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+			if isNested(field.Type) {
+				if field.Type.Kind() == reflect.Pointer {
+					if ov.IsNil() || cv.IsNil() {
+						continue
+					}
+					ov, cv = ov.Elem(), cv.Elem()
+				}
+				changes = diffStruct(ov, cv, prefix+field.Tag.Get(TagPrefix), path+field.Name+".", changes)
+			}
+			continue
+		}
+		if !field.IsExported() || reflect.DeepEqual(ov.Interface(), cv.Interface()) {
+			continue
+		}
+		changes = append(changes, Change{
+			Key:   prefix + key,
+			Field: path + field.Name,
+			Old:   ov.Interface(),
+			New:   cv.Interface(),
+		})
+	}
+	return changes
+}
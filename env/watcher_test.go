@@ -0,0 +1,147 @@
+package env_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/env"
+	"go.uber.org/fx/fxtest"
+)
+
+type watchLimits struct {
+	RPS int `env:"RPS" default:"100"`
+}
+
+type watchConfig struct {
+	Level  string      `env:"WATCH_LEVEL" default:"info"`
+	Limits watchLimits `prefix:"WATCH_"`
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("WATCH_LEVEL=info\n"), 0o600))
+	prev := env.CurrentSource()
+	t.Cleanup(func() { env.SetSource(prev) })
+	files, err := env.LoadDotenv(path)
+	require.NoError(t, err)
+
+	w, err := env.NewWatcher[watchConfig](10*time.Millisecond, files)
+	require.NoError(t, err)
+	assert.Equal(t, "info", w.Get().Level)
+
+	notified := make(chan []env.Change, 1)
+	w.Subscribe(func(old, cur *watchConfig, changes []env.Change) {
+		assert.Equal(t, "info", old.Level)
+		assert.Equal(t, "debug", cur.Level)
+		notified <- changes
+	})
+
+	lc := fxtest.NewLifecycle(t)
+	w.Register(lc)
+	lc.RequireStart()
+
+	require.NoError(t, os.WriteFile(path, []byte("WATCH_LEVEL=debug\nWATCH_RPS=100\n"), 0o600))
+	select {
+	case changes := <-notified:
+		assert.Equal(t, []env.Change{{
+			Key:   "WATCH_LEVEL",
+			Field: "Level",
+			Old:   "info",
+			New:   "debug",
+		}}, changes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not notify")
+	}
+	assert.Equal(t, "debug", w.Get().Level)
+
+	lc.RequireStop()
+}
+
+func TestWatcher_KeepsConfigOnFailure(t *testing.T) {
+	src := env.Map{"WATCH_LEVEL": "warn"}
+	defer env.SetSource(env.SetSource(src))
+
+	w, err := env.NewWatcher[watchConfig](0)
+	require.NoError(t, err)
+
+	src["WATCH_RPS"] = "many"
+	_, err = w.Reload()
+	assert.ErrorIs(t, err, env.ErrInvalid)
+	assert.Equal(t, 100, w.Get().Limits.RPS)
+
+	src["WATCH_RPS"] = "5"
+	changes, err := w.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []env.Change{{Key: "WATCH_RPS", Field: "Limits.RPS", Old: 100, New: 5}}, changes)
+	require.NoError(t, w.Stop(context.Background()))
+}
+
+type countingSource struct {
+	env.Map
+	modified atomic.Bool
+	reloads  atomic.Int32
+}
+
+func (s *countingSource) Modified() bool {
+	return s.modified.Load()
+}
+
+func (s *countingSource) Reload() error {
+	s.reloads.Add(1)
+	s.modified.Store(false)
+	return nil
+}
+
+func TestWatcher_ReloadsModifiedOnly(t *testing.T) {
+	src := &countingSource{Map: env.Map{}}
+	defer env.SetSource(env.SetSource(src))
+
+	w, err := env.NewWatcher[watchConfig](time.Millisecond, src)
+	require.NoError(t, err)
+	require.NoError(t, w.Start(context.Background()))
+	defer w.Stop(context.Background())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, src.reloads.Load())
+
+	src.modified.Store(true)
+	assert.Eventually(t, func() bool { return src.reloads.Load() == 1 }, 5*time.Second, time.Millisecond)
+}
+
+func TestWatcher_SecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	require.NoError(t, os.WriteFile(path, []byte("info"), 0o600))
+	defer env.SetSource(env.SetSource(env.Map{"WATCH_LEVEL_FILE": path}))
+
+	w, err := env.NewWatcher[watchConfig](time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "info", w.Get().Level)
+	require.NoError(t, w.Start(context.Background()))
+	defer w.Stop(context.Background())
+
+	require.NoError(t, os.WriteFile(path, []byte("debug"), 0o600))
+	assert.Eventually(t, func() bool { return w.Get().Level == "debug" }, 5*time.Second, time.Millisecond)
+}
+
+func TestWatcher_DotenvCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	prev := env.CurrentSource()
+	t.Cleanup(func() { env.SetSource(prev) })
+	files, err := env.LoadDotenv(path)
+	require.NoError(t, err)
+
+	w, err := env.NewWatcher[watchConfig](time.Millisecond, files)
+	require.NoError(t, err)
+	assert.Equal(t, "info", w.Get().Level)
+	require.NoError(t, w.Start(context.Background()))
+	defer w.Stop(context.Background())
+
+	require.NoError(t, os.WriteFile(path, []byte("WATCH_LEVEL=debug\n"), 0o600))
+	assert.Eventually(t, func() bool { return w.Get().Level == "debug" }, 5*time.Second, time.Millisecond)
+}
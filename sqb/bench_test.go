@@ -0,0 +1,44 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+// Compare the builder against another revision, e.g. the parent commit, in a worktree of it with benchstat:
+//
+//	git worktree add /tmp/sqb-old HEAD~1
+//	go test -run '^$' -bench Builder -count 10 ./sqb > new.txt
+//	(cd /tmp/sqb-old && go test -run '^$' -bench Builder -count 10 ./sqb) > old.txt
+//	benchstat old.txt new.txt
+//
+// Revisions older than these benchmarks need a copy of this file, without the ones using missing methods like Clone.
+
+func BenchmarkBuilder_Select(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		qb := sqb.GetBuilder()
+		qb.Select("id", "name", "email").
+			From("users").
+			Where("age > ?", 18).
+			Where("status = ?", "active").
+			OrderBy("created_at", sqb.Desc).
+			OrderBy("id", sqb.Desc).
+			Limit(20).
+			Sql()
+		sqb.RealiseBuilder(qb)
+	}
+}
+
+func BenchmarkBuilder_Clone(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		base := sqb.GetBuilder().From("users").Where("age > ?", 18).Where("status = ?", "active")
+		count := base.Clone().Select("count(*)")
+		count.Sql()
+		base.Select("id", "name", "email").OrderBy("id", sqb.Desc).Limit(20).Sql()
+		sqb.RealiseBuilder(count)
+		sqb.RealiseBuilder(base)
+	}
+}
@@ -0,0 +1,189 @@
+package sqb
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// mark stands for a placeholder until the query is rendered by its dialect,
+// so that placeholders are numbered in the order they appear in the final SQL
+const mark = '\x00'
+
+// buffer holds a part of the query with placeholder marks and the args bound to them, in order
+type buffer struct {
+	sql  []byte
+	args []any
+}
+
+// reset empties the buffer keeping its capacity, it leaves empty buffers untouched,
+// which is most of the clauses of a builder
+func (b *buffer) reset() {
+	if len(b.sql) > 0 {
+		b.sql = b.sql[:0]
+	}
+	if len(b.args) > 0 {
+		clear(b.args)
+		b.args = b.args[:0]
+	}
+}
+
+func (b *buffer) write(s string) {
+	b.sql = append(b.sql, s...)
+}
+
+func (b *buffer) writeByte(c byte) {
+	b.sql = append(b.sql, c)
+}
+
+func (b *buffer) writeInt(n int) {
+	b.sql = strconv.AppendInt(b.sql, int64(n), 10)
+}
+
+// append appends the query part of b along with its args
+func (b *buffer) append(part *buffer) {
+	if len(part.sql) == 0 {
+		return
+	}
+	b.sql = append(b.sql, part.sql...)
+	if len(part.args) > 0 {
+		b.args = append(b.args, part.args...)
+	}
+}
+
+// list appends comma separated items followed by a space
+func (b *buffer) list(items []string) {
+	for i, item := range items {
+		if i > 0 {
+			b.write(", ")
+		}
+		b.write(item)
+	}
+	b.writeByte(' ')
+}
+
+// parenList appends parenthesized comma separated items followed by a space
+func (b *buffer) parenList(items []string) {
+	b.writeByte('(')
+	b.list(items)
+	b.sql[len(b.sql)-1] = ')'
+	b.writeByte(' ')
+}
+
+// comma replaces the trailing space left by the previous list item with a comma
+func (b *buffer) comma() {
+	b.sql = append(b.sql[:len(b.sql)-1], ", "...)
+}
+
+// arg appends a placeholder bound to value, a *QueryBuilder value is embedded as a subquery instead,
+// as is a subquery already rendered by an Expr constructor, and the slice of a List is expanded
+func (b *buffer) arg(value any) {
+	switch v := value.(type) {
+	case *QueryBuilder:
+		b.embed(v)
+		return
+	case *buffer:
+		b.append(v)
+		return
+	case list:
+		if listLen(v.values) == 0 {
+			b.write("NULL")
+		} else {
+			b.expand(v.values)
+		}
+		return
+	}
+	b.sql = append(b.sql, mark)
+	b.args = append(b.args, value)
+}
+
+// expand appends a comma separated placeholder for every element of the slice values
+func (b *buffer) expand(values any) {
+	if vs, ok := values.([]any); ok {
+		for i, v := range vs {
+			if i > 0 {
+				b.write(", ")
+			}
+			b.arg(v)
+		}
+		return
+	}
+	rv := reflect.ValueOf(values)
+	for i := range rv.Len() {
+		if i > 0 {
+			b.write(", ")
+		}
+		b.arg(rv.Index(i).Interface())
+	}
+}
+
+// bind appends clause replacing each '?' with a placeholder bound to the next of args.
+// A *QueryBuilder argument is embedded as a subquery instead, without parentheses.
+// A clause without args is appended as is, so that operators like jsonb '?' survive.
+func (b *buffer) bind(clause string, args []any) {
+	if len(args) == 0 {
+		b.write(clause)
+		return
+	}
+	if strings.Count(clause, "?") != len(args) {
+		panic("number of placeholders does not match args")
+	}
+	next := 0
+	for i := range len(clause) {
+		if clause[i] != '?' {
+			b.writeByte(clause[i])
+			continue
+		}
+		b.arg(args[next])
+		next++
+	}
+}
+
+// embed appends the query of sub along with its args and releases sub to the pool
+func (b *buffer) embed(sub *QueryBuilder) {
+	if sub.owns(b) {
+		panic("query embedded into itself")
+	}
+	q := sub.assemble()
+	b.sql = append(b.sql, trimSpace(q.sql)...)
+	b.args = append(b.args, q.args...)
+	RealiseBuilder(sub)
+}
+
+// rendered returns the query of sub along with its args and releases sub to the pool,
+// so that expressions holding a subquery can be rendered more than once
+func rendered(sub *QueryBuilder) *buffer {
+	b := new(buffer)
+	b.embed(sub)
+	return b
+}
+
+// renderedArg returns value with a *QueryBuilder replaced by its rendered query
+func renderedArg(value any) any {
+	if sub, ok := value.(*QueryBuilder); ok {
+		return rendered(sub)
+	}
+	return value
+}
+
+// renderedArgs returns args with every *QueryBuilder replaced by its rendered query, copying args only then
+func renderedArgs(args []any) []any {
+	copied := false
+	for i, arg := range args {
+		if sub, ok := arg.(*QueryBuilder); ok {
+			if !copied {
+				args, copied = slices.Clone(args), true
+			}
+			args[i] = rendered(sub)
+		}
+	}
+	return args
+}
+
+func trimSpace(sql []byte) []byte {
+	for len(sql) > 0 && sql[len(sql)-1] == ' ' {
+		sql = sql[:len(sql)-1]
+	}
+	return sql
+}
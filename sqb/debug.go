@@ -0,0 +1,274 @@
+package sqb
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xakepp35/pkg/xhash"
+)
+
+// Debug returns the query with every arg inlined as a quoted literal of the dialect, for logs and support tickets.
+// Strings are escaped, so the result is safe to read and to paste into a console,
+// but queries are run with Sql: literals lose the types the driver gives to args.
+func (qb *QueryBuilder) Debug() string {
+	q := qb.assemble()
+	sql := trimSpace(q.sql)
+	var b strings.Builder
+	b.Grow(len(sql) + 16*len(q.args))
+	for n := 0; ; n++ {
+		i := bytes.IndexByte(sql, mark)
+		if i < 0 {
+			b.Write(sql)
+			break
+		}
+		b.Write(sql[:i])
+		sql = sql[i+1:]
+		qb.dialect.literal(&b, q.args[n])
+	}
+	return b.String()
+}
+
+// Fingerprint returns the fingerprint of the query shape, see Fingerprint.
+func (qb *QueryBuilder) Fingerprint() uint64 {
+	sql, _ := qb.Sql()
+	return Fingerprint(sql)
+}
+
+// Fingerprint hashes the normalised sql, so that queries differing only in values, e.g. LIMIT 10 and LIMIT 20,
+// or in the number of values of a list share it. Query stats can be aggregated per fingerprint.
+func Fingerprint(sql string) uint64 {
+	return xhash.HashString64(Normalize(sql))
+}
+
+// Normalize replaces the placeholders, string and number literals of sql with '?', signed and exponent ones
+// like -5 or 1.5e-3 included, collapses lists of them
+// into a single '?' and whitespace and comments into a single space:
+//
+//	SELECT * FROM t WHERE a IN ($1, $2) AND b = 'x' LIMIT 10 -- page
+//	SELECT * FROM t WHERE a IN (?) AND b = ? LIMIT ?
+func Normalize(sql string) string {
+	out := make([]byte, 0, len(sql))
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			out = space(out)
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			out = space(out)
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 4
+			}
+			out = space(out)
+		case c == '\'':
+			for i++; i < len(sql); i++ {
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			i++
+			out = placeholder(out)
+		case c == '"' || c == '`':
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				end = len(sql) - i - 1
+			} else {
+				end++
+			}
+			out = append(out, sql[i:i+end+1]...)
+			i += end + 1
+		case c == '?' || c == '$' && i+1 < len(sql) && isDigit(sql[i+1]):
+			for i++; i < len(sql) && isDigit(sql[i]); i++ {
+			}
+			out = placeholder(out)
+		case isNumber(sql[i:], out):
+			i += numberLen(sql[i:])
+			out = placeholder(out)
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+	return string(bytes.TrimSpace(out))
+}
+
+// space appends a single space separating tokens
+func space(out []byte) []byte {
+	if len(out) == 0 || out[len(out)-1] == ' ' {
+		return out
+	}
+	return append(out, ' ')
+}
+
+// placeholder appends '?' unless it continues a list of them
+func placeholder(out []byte) []byte {
+	trimmed := bytes.TrimRight(out, " ")
+	if prev, ok := bytes.CutSuffix(trimmed, []byte{','}); ok && bytes.HasSuffix(bytes.TrimRight(prev, " "), []byte{'?'}) {
+		return bytes.TrimRight(prev, " ")
+	}
+	return append(out, '?')
+}
+
+// isNumber reports whether s starts with a number literal, out being the normalised sql before it.
+// A sign belongs to the number unless it follows an operand, as in a - 5.
+func isNumber(s string, out []byte) bool {
+	if s[0] == '-' || s[0] == '+' {
+		if isOperand(out) {
+			return false
+		}
+		s = s[1:]
+	} else if isWord(out) {
+		return false
+	}
+	return len(s) > 0 && isDigit(s[0]) || len(s) > 1 && s[0] == '.' && isDigit(s[1])
+}
+
+// numberLen returns the length of the number literal s starts with, sign and exponent included
+func numberLen(s string) int {
+	i := 0
+	if s[0] == '-' || s[0] == '+' {
+		i++
+	}
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '-' || s[j] == '+') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for i = j; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+	}
+	return i
+}
+
+// keywords followed by operands, after which a sign starts a number
+var keywords = map[string]bool{
+	"SELECT": true, "WHERE": true, "AND": true, "OR": true, "NOT": true, "ON": true, "HAVING": true,
+	"LIMIT": true, "OFFSET": true, "VALUES": true, "SET": true, "BY": true, "IN": true, "IS": true,
+	"LIKE": true, "BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "RETURNING": true,
+}
+
+// isOperand reports whether out ends with an operand, like a column, a literal or a closing parenthesis
+func isOperand(out []byte) bool {
+	out = bytes.TrimRight(out, " ")
+	if len(out) == 0 {
+		return false
+	}
+	switch out[len(out)-1] {
+	case ')', ']', '?', '"', '`':
+		return true
+	}
+	if !isWord(out) {
+		return false
+	}
+	i := len(out)
+	for i > 0 && isWord(out[:i]) {
+		i--
+	}
+	return !keywords[strings.ToUpper(string(out[i:]))]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isWord reports whether out ends inside an identifier, like t1
+func isWord(out []byte) bool {
+	if len(out) == 0 {
+		return false
+	}
+	c := out[len(out)-1]
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// literal writes v as a literal of the dialect
+func (d *Dialect) literal(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("NULL")
+	case bool:
+		b.WriteString(d.Bool(v))
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		fmt.Fprint(b, v)
+	case float32:
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		d.quoteString(b, v)
+	case []byte:
+		if d.bytea {
+			b.WriteString(`'\x`)
+		} else {
+			b.WriteString("X'")
+		}
+		b.WriteString(hex.EncodeToString(v))
+		b.WriteByte('\'')
+	case time.Time:
+		d.quoteString(b, v.Format(time.RFC3339Nano))
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			b.WriteString("NULL")
+			return
+		}
+		value, err := v.Value()
+		if err != nil {
+			d.quoteString(b, fmt.Sprint(v))
+			return
+		}
+		d.literal(b, value)
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			d.quoteString(b, fmt.Sprint(v))
+			return
+		}
+		b.WriteString("ARRAY[")
+		for i := range rv.Len() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			d.literal(b, rv.Index(i).Interface())
+		}
+		b.WriteByte(']')
+	}
+}
+
+// quoteString writes s as a quoted string literal, doubling the quotes inside it
+func (d *Dialect) quoteString(b *strings.Builder, s string) {
+	b.WriteByte('\'')
+	for i := range len(s) {
+		switch c := s[i]; {
+		case c == '\'':
+			b.WriteString("''")
+		case c == '\\' && d.backslash:
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+}
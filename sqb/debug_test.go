@@ -0,0 +1,60 @@
+package sqb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+	"github.com/xakepp35/pkg/src/pkg/types"
+)
+
+func TestDebug(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	qb.Select("id").
+		From("users").
+		Where("name = ? OR name = ?", "O'Brien'; DROP TABLE users; --", `a\b`).
+		Where("created_at > ? AND updated_at < ?", at, types.NewTime(at)).
+		Where("active = ? AND deleted_at IS ?", true, nil).
+		Where("avatar = ? AND score > ?", []byte{0xca, 0xfe}, 1.5).
+		WhereExpr(sqb.In("id", []int64{1, 2}))
+	assert.Equal(t, `SELECT id FROM users WHERE (name = 'O''Brien''; DROP TABLE users; --' OR name = 'a\b') `+
+		`AND (created_at > '2024-01-02T03:04:05Z' AND updated_at < '2024-01-02T03:04:05Z') `+
+		`AND (active = TRUE AND deleted_at IS NULL) AND (avatar = '\xcafe' AND score > 1.5) AND (id = ANY(ARRAY[1, 2]))`,
+		qb.Debug())
+
+	sql, args := qb.Sql()
+	assert.Contains(t, sql, "$9")
+	assert.Len(t, args, 9)
+}
+
+func TestDebug_MySQL(t *testing.T) {
+	qb := sqb.GetBuilder().SetDialect(sqb.MySQL)
+	defer sqb.RealiseBuilder(qb)
+
+	qb.Select("id").From("users").Where("name = ? AND avatar = ? AND admin = ?", `x\' OR 1=1 --`, []byte{1}, false)
+	assert.Equal(t, `SELECT id FROM users WHERE (name = 'x\\'' OR 1=1 --' AND avatar = X'01' AND admin = FALSE)`, qb.Debug())
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM t1 WHERE a IN (?) AND b = ? AND \"c 1\" = ? LIMIT ?",
+		sqb.Normalize("SELECT *\n  FROM t1 -- table\nWHERE a IN ($1, $2, $3) AND b = 'it''s' /* x */ AND \"c 1\" = 42 LIMIT 10"))
+	assert.Equal(t, "SELECT a - ? FROM t WHERE b IN (?) AND c > ? AND d = ? AND e = ? LIMIT ?",
+		sqb.Normalize("SELECT a - 5 FROM t WHERE b IN (-1, +2, 1e5) AND c > 1.5E-3 AND d = .5 AND e = -2.5 LIMIT -1"))
+	assert.Equal(t, sqb.Fingerprint("SELECT * FROM t WHERE a = 5"), sqb.Fingerprint("SELECT * FROM t WHERE a = -5e3"))
+}
+
+func TestFingerprint(t *testing.T) {
+	page := func(limit int, ids ...any) uint64 {
+		qb := sqb.GetBuilder()
+		defer sqb.RealiseBuilder(qb)
+		return qb.Select("id").From("users").WhereExpr(sqb.In("id", ids...)).Limit(limit).Fingerprint()
+	}
+	assert.Equal(t, page(10, 1, 2), page(20, 3, 4, 5))
+	assert.NotEqual(t, page(10, 1), sqb.Fingerprint("SELECT id FROM users WHERE (id IN (?))"))
+	assert.Equal(t, sqb.Fingerprint("SELECT id FROM users WHERE (id IN (?)) LIMIT ?"), page(10, 1))
+}
@@ -0,0 +1,150 @@
+package sqb
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Dialect describes the SQL flavour a query is rendered for.
+// Placeholders are rendered by Sql, the rest of the differences are applied while building,
+// so set the dialect before the first clause.
+type Dialect struct {
+	name      string
+	numbered  bool   // $1, $2, ... instead of ?
+	quote     byte   // identifier quote
+	returning bool   // RETURNING is supported
+	arrays    bool   // slices bind as arrays
+	locking   bool   // FOR UPDATE and FOR SHARE are supported, not FOR NO KEY UPDATE unless keyLocks
+	keyLocks  bool   // FOR NO KEY UPDATE is supported
+	parenOps  bool   // operands of UNION, INTERSECT and EXCEPT may be parenthesised
+	bytea     bool   // byte literals are written as '\x...' instead of X'...'
+	backslash bool   // backslashes escape in string literals
+	noLimit   string // LIMIT written before OFFSET without Limit, empty when OFFSET may stand alone
+	true      string
+	false     string
+}
+
+var (
+	// Postgres renders $n placeholders
+	Postgres = &Dialect{
+		name:      "postgres",
+		numbered:  true,
+		quote:     '"',
+		returning: true,
+		arrays:    true,
+		locking:   true,
+		keyLocks:  true,
+		parenOps:  true,
+		bytea:     true,
+		true:      "TRUE",
+		false:     "FALSE",
+	}
+	// SQLite renders ? placeholders, it also serves rqlite
+	SQLite = &Dialect{
+		name:      "sqlite",
+		quote:     '"',
+		returning: true,
+		noLimit:   "-1",
+		true:      "1",
+		false:     "0",
+	}
+	// MySQL renders ? placeholders and has no RETURNING
+	MySQL = &Dialect{
+		name:      "mysql",
+		quote:     '`',
+		noLimit:   "18446744073709551615",
+		locking:   true,
+		parenOps:  true,
+		backslash: true,
+		true:      "TRUE",
+		false:     "FALSE",
+	}
+)
+
+var defaultDialect atomic.Pointer[Dialect]
+
+func init() {
+	defaultDialect.Store(Postgres)
+}
+
+// SetDefaultDialect sets the dialect of the builders taken from the pool and returns the previous one
+func SetDefaultDialect(d *Dialect) *Dialect {
+	return defaultDialect.Swap(d)
+}
+
+// SetDialect sets the dialect of the builder, it is reset to the default one on Reset.
+// Clauses and locks are checked against the dialect as they are added, so it panics once any of them was.
+func (qb *QueryBuilder) SetDialect(d *Dialect) *QueryBuilder {
+	for _, b := range qb.all() {
+		if len(b.sql) > 0 {
+			panic("sqb: SetDialect after clauses were written")
+		}
+	}
+	if qb.lock != "" {
+		panic("sqb: SetDialect after a locking clause")
+	}
+	qb.dialect = d
+	return qb
+}
+
+// Dialect returns the dialect of the builder
+func (qb *QueryBuilder) Dialect() *Dialect {
+	return qb.dialect
+}
+
+// String returns the name of the dialect
+func (d *Dialect) String() string {
+	return d.name
+}
+
+// Quote quotes an identifier, escaping the quotes inside it.
+// Dots separate qualified names: "public.users" becomes "public"."users".
+func (d *Dialect) Quote(name string) string {
+	var b strings.Builder
+	b.Grow(len(name) + 2)
+	q := string(d.quote)
+	for i, part := range strings.Split(name, ".") {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteByte(d.quote)
+		b.WriteString(strings.ReplaceAll(part, q, q+q))
+		b.WriteByte(d.quote)
+	}
+	return b.String()
+}
+
+// Bool returns the boolean literal
+func (d *Dialect) Bool(v bool) string {
+	if v {
+		return d.true
+	}
+	return d.false
+}
+
+// render replaces the placeholder marks of sql
+func (d *Dialect) render(sql []byte) string {
+	sql = trimSpace(sql)
+	var b strings.Builder
+	b.Grow(len(sql) + 8)
+	n := 0
+	for {
+		i := bytes.IndexByte(sql, mark)
+		if i < 0 {
+			b.Write(sql)
+			break
+		}
+		b.Write(sql[:i])
+		sql = sql[i+1:]
+		n++
+		if d.numbered {
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestDialect_Placeholders(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	banned := sqb.GetBuilder().Select("user_id").From("bans").Where("reason = ?", "spam")
+	sql, args := qb.SetDialect(sqb.SQLite).
+		Select("u.id").
+		From("users u").
+		Where("u.age > ?", 18).
+		Where("u.id NOT IN (?)", banned).
+		Join("teams t", "t.id = u.team_id AND t.region = ?", "eu").
+		WhereExpr(sqb.In("u.role")).
+		Offset(20).
+		Sql()
+	assert.Equal(t, "SELECT u.id FROM users u JOIN teams t ON t.id = u.team_id AND t.region = ? "+
+		"WHERE (u.age > ?) AND (u.id NOT IN (SELECT user_id FROM bans WHERE (reason = ?))) AND (0) LIMIT -1 OFFSET 20", sql)
+	assert.Equal(t, []any{"eu", 18, "spam"}, args)
+}
+
+func TestDialect_MySQL(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.SetDialect(sqb.MySQL).
+		Select(sqb.MySQL.Quote("order")).
+		From("t").
+		Where("a = ?", 1).
+		Limit(5).
+		Offset(10).
+		Sql()
+	assert.Equal(t, "SELECT `order` FROM t WHERE (a = ?) LIMIT 5 OFFSET 10", sql)
+	assert.Equal(t, []any{1}, args)
+
+	assert.Panics(t, func() {
+		qb.Returning("id")
+	})
+}
+
+func TestSetDialect_AfterClauses(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	qb.Select("id").From("users").WhereExpr(sqb.In("id", []int{1, 2}))
+	assert.Panics(t, func() {
+		qb.SetDialect(sqb.SQLite)
+	})
+
+	qb.Reset()
+	sql, _ := qb.SetDialect(sqb.SQLite).Select("id").From("users").WhereExpr(sqb.In("id", []int{1, 2})).Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (id IN (?, ?))", sql)
+}
+
+func TestSetDefaultDialect(t *testing.T) {
+	defer sqb.SetDefaultDialect(sqb.SetDefaultDialect(sqb.SQLite))
+
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Equal(t, sqb.SQLite, qb.Dialect())
+	sql, _ := qb.Update("users").Set("active", true).Where("id = ?", 1).Sql()
+	assert.Equal(t, "UPDATE users SET active = ? WHERE (id = ?)", sql)
+}
+
+func TestDialect_Quote(t *testing.T) {
+	assert.Equal(t, `"public"."user"`, sqb.Postgres.Quote("public.user"))
+	assert.Equal(t, "`we``ird`", sqb.MySQL.Quote("we`ird"))
+	assert.Equal(t, "1", sqb.SQLite.Bool(true))
+	assert.Equal(t, "FALSE", sqb.Postgres.Bool(false))
+	assert.Equal(t, "sqlite", sqb.SQLite.String())
+}
@@ -0,0 +1,169 @@
+package sqb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/grpc/codes"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// Querier runs queries, it is satisfied by *pgxpool.Pool, *pgx.Conn and the pgx.Tx of xpgx.TxManager.Do
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// ErrColumnUnknown is returned when a query returns a column the scanned struct has no db field for
+var ErrColumnUnknown = errors.New("column has no matching struct field")
+
+// Query runs the query on q. The builder is released to the pool when the rows are closed or exhausted,
+// or right away when the query fails, so it must not be used afterwards.
+func (qb *QueryBuilder) Query(ctx context.Context, q Querier) (pgx.Rows, error) {
+	sql, args := qb.Sql()
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		RealiseBuilder(qb)
+		return nil, err
+	}
+	return &builderRows{
+		Rows: rows,
+		qb:   qb,
+	}, nil
+}
+
+// QueryRow runs the query on q. The builder is released to the pool by Scan of the returned row.
+func (qb *QueryBuilder) QueryRow(ctx context.Context, q Querier) pgx.Row {
+	sql, args := qb.Sql()
+	return &builderRow{
+		row: q.QueryRow(ctx, sql, args...),
+		qb:  qb,
+	}
+}
+
+// Exec runs the statement on q and releases the builder to the pool.
+func (qb *QueryBuilder) Exec(ctx context.Context, q Querier) (pgconn.CommandTag, error) {
+	defer RealiseBuilder(qb)
+	sql, args := qb.Sql()
+	return q.Exec(ctx, sql, args...)
+}
+
+// All runs the query of qb on q and scans every row into a T, then releases the builder.
+// Structs with db tags are filled by column name, other types are scanned from a single column.
+func All[T any](ctx context.Context, q Querier, qb *QueryBuilder) ([]T, error) {
+	rows, err := qb.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []T
+	scan := rowScanner[T](rows)
+	for rows.Next() {
+		var v T
+		if err := scan(&v); err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, rows.Err()
+}
+
+// One runs the query of qb on q and scans the first row into a T like All, then releases the builder.
+// No rows yield a NotFound error matching pgx.ErrNoRows.
+func One[T any](ctx context.Context, q Querier, qb *QueryBuilder) (T, error) {
+	var v T
+	rows, err := qb.Query(ctx, q)
+	if err != nil {
+		return v, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return v, err
+		}
+		return v, xerrors.Err(pgx.ErrNoRows).Proto(codes.NotFound)
+	}
+	if err := rowScanner[T](rows)(&v); err != nil {
+		return v, err
+	}
+	rows.Close()
+	return v, rows.Err()
+}
+
+// rowScanner returns the function scanning the current row of rows into a T
+func rowScanner[T any](rows pgx.Rows) func(*T) error {
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() != reflect.Struct || len(metaOf(rt).fields) == 0 {
+		return func(v *T) error {
+			return rows.Scan(v)
+		}
+	}
+	meta := metaOf(rt)
+	fields := make([]int, len(rows.FieldDescriptions()))
+	var unknown error
+	for i, fd := range rows.FieldDescriptions() {
+		f, ok := meta.byColumn[fd.Name]
+		if !ok && unknown == nil {
+			unknown = xerrors.Err(ErrColumnUnknown).
+				Str("column", fd.Name).
+				Str("type", rt.String()).
+				Send()
+		}
+		fields[i] = f
+	}
+	targets := make([]any, len(fields))
+	return func(v *T) error {
+		if unknown != nil {
+			return unknown
+		}
+		rv := reflect.ValueOf(v).Elem()
+		for i, f := range fields {
+			targets[i] = rv.FieldByIndex(meta.fields[f].index).Addr().Interface()
+		}
+		return rows.Scan(targets...)
+	}
+}
+
+type builderRows struct {
+	pgx.Rows
+	qb *QueryBuilder
+}
+
+func (r *builderRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.release()
+	return false
+}
+
+func (r *builderRows) Close() {
+	r.Rows.Close()
+	r.release()
+}
+
+func (r *builderRows) release() {
+	if r.qb != nil {
+		RealiseBuilder(r.qb)
+		r.qb = nil
+	}
+}
+
+type builderRow struct {
+	row pgx.Row
+	qb  *QueryBuilder
+}
+
+func (r *builderRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if r.qb != nil {
+		RealiseBuilder(r.qb)
+		r.qb = nil
+	}
+	return err
+}
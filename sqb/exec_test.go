@@ -0,0 +1,182 @@
+package sqb_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+var (
+	_ sqb.Querier = (*pgxpool.Pool)(nil)
+	_ sqb.Querier = (*pgx.Conn)(nil)
+	_ sqb.Querier = (pgx.Tx)(nil)
+)
+
+// fakeQuerier records the last query and returns rows of values for the specified columns
+type fakeQuerier struct {
+	sql     string
+	args    []any
+	columns []string
+	values  [][]any
+	err     error
+}
+
+func (q *fakeQuerier) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.sql, q.args = sql, append([]any(nil), args...)
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &fakeRows{columns: q.columns, values: q.values, pos: -1}, nil
+}
+
+func (q *fakeQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := q.Query(ctx, sql, args...)
+	return fakeRow{rows, err}
+}
+
+func (q *fakeQuerier) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.sql, q.args = sql, append([]any(nil), args...)
+	return pgconn.NewCommandTag("UPDATE 1"), q.err
+}
+
+type fakeRows struct {
+	pgx.Rows
+	columns []string
+	values  [][]any
+	pos     int
+	closed  bool
+}
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fds := make([]pgconn.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		fds[i].Name = c
+	}
+	return fds
+}
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	if r.pos >= len(r.values) {
+		r.closed = true
+		return false
+	}
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[r.pos][i]))
+	}
+	return nil
+}
+
+func (r *fakeRows) Close()     { r.closed = true }
+func (r *fakeRows) Err() error { return nil }
+
+type fakeRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+type account struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestAll(t *testing.T) {
+	q := &fakeQuerier{
+		columns: []string{"name", "id"},
+		values:  [][]any{{"alice", int64(1)}, {"bob", int64(2)}},
+	}
+	qb := sqb.GetBuilder().Select("name", "id").From("accounts").Where("id > ?", 0)
+
+	res, err := sqb.All[account](context.Background(), q, qb)
+	require.NoError(t, err)
+	assert.Equal(t, []account{{1, "alice"}, {2, "bob"}}, res)
+	assert.Equal(t, "SELECT name, id FROM accounts WHERE (id > $1)", q.sql)
+	assert.Equal(t, []any{0}, q.args)
+}
+
+func TestAll_Scalar(t *testing.T) {
+	q := &fakeQuerier{
+		columns: []string{"id"},
+		values:  [][]any{{int64(1)}, {int64(2)}},
+	}
+	res, err := sqb.All[int64](context.Background(), q, sqb.GetBuilder().Select("id").From("accounts"))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, res)
+}
+
+func TestAll_UnknownColumn(t *testing.T) {
+	q := &fakeQuerier{
+		columns: []string{"id", "password"},
+		values:  [][]any{{int64(1), "x"}},
+	}
+	_, err := sqb.All[account](context.Background(), q, sqb.GetBuilder().Select("*").From("accounts"))
+	assert.ErrorIs(t, err, sqb.ErrColumnUnknown)
+}
+
+func TestOne(t *testing.T) {
+	q := &fakeQuerier{
+		columns: []string{"id", "name"},
+		values:  [][]any{{int64(1), "alice"}},
+	}
+	v, err := sqb.One[account](context.Background(), q, sqb.GetBuilder().Select("id", "name").From("accounts"))
+	require.NoError(t, err)
+	assert.Equal(t, account{1, "alice"}, v)
+
+	q.values = nil
+	_, err = sqb.One[account](context.Background(), q, sqb.GetBuilder().Select("id", "name").From("accounts"))
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestQuery_Error(t *testing.T) {
+	q := &fakeQuerier{err: errors.New("boom")}
+	_, err := sqb.GetBuilder().Select("1").Query(context.Background(), q)
+	assert.EqualError(t, err, "boom")
+}
+
+func TestQueryRow(t *testing.T) {
+	q := &fakeQuerier{
+		columns: []string{"count"},
+		values:  [][]any{{int64(3)}},
+	}
+	var n int64
+	err := sqb.GetBuilder().Select("count(*)").From("accounts").QueryRow(context.Background(), q).Scan(&n)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, "SELECT count(*) FROM accounts", q.sql)
+}
+
+func TestExec(t *testing.T) {
+	q := &fakeQuerier{}
+	tag, err := sqb.GetBuilder().Update("accounts").Set("name", "bob").Where("id = ?", 1).Exec(context.Background(), q)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), tag.RowsAffected())
+	assert.Equal(t, "UPDATE accounts SET name = $1 WHERE (id = $2)", q.sql)
+	assert.Equal(t, []any{"bob", 1}, q.args)
+}
@@ -0,0 +1,273 @@
+package sqb
+
+// Expr is a condition of the WHERE clause, built with Eq, In, And, Or and the other constructors.
+// Values are always bound as placeholders, columns are written as is.
+type Expr interface {
+	appendExpr(qb *QueryBuilder, b *buffer)
+	empty() bool
+}
+
+type compareExpr struct {
+	col   string
+	op    string
+	value any
+}
+
+// Eq renders col = value.
+func Eq(col string, value any) Expr {
+	return compareExpr{col, " = ", renderedArg(value)}
+}
+
+// Ne renders col <> value.
+func Ne(col string, value any) Expr {
+	return compareExpr{col, " <> ", renderedArg(value)}
+}
+
+// Gt renders col > value.
+func Gt(col string, value any) Expr {
+	return compareExpr{col, " > ", renderedArg(value)}
+}
+
+// Ge renders col >= value.
+func Ge(col string, value any) Expr {
+	return compareExpr{col, " >= ", renderedArg(value)}
+}
+
+// Lt renders col < value.
+func Lt(col string, value any) Expr {
+	return compareExpr{col, " < ", renderedArg(value)}
+}
+
+// Le renders col <= value.
+func Le(col string, value any) Expr {
+	return compareExpr{col, " <= ", renderedArg(value)}
+}
+
+// Like renders col LIKE pattern.
+func Like(col string, pattern string) Expr {
+	return compareExpr{col, " LIKE ", pattern}
+}
+
+// ILike renders col ILIKE pattern.
+func ILike(col string, pattern string) Expr {
+	return compareExpr{col, " ILIKE ", pattern}
+}
+
+func (e compareExpr) appendExpr(qb *QueryBuilder, b *buffer) {
+	b.write(e.col)
+	b.write(e.op)
+	b.arg(e.value)
+}
+
+func (compareExpr) empty() bool {
+	return false
+}
+
+type inExpr struct {
+	col    string
+	values any
+	not    bool
+}
+
+// In renders the membership of col in values, which are either listed one by one or passed as a single slice:
+//
+//	sqb.In("status", "new", "active") // status IN ($1, $2)
+//	sqb.In("id", ids)                 // id = ANY($1) on Postgres, id IN ($1, $2, ...) elsewhere
+//
+// A slice is bound as a single array on dialects with arrays. No values render false since nothing matches.
+func In(col string, values ...any) Expr {
+	return inExpr{col: col, values: listOf(renderedArgs(values))}
+}
+
+// NotIn renders the negation of In, as col <> ALL($n) for a slice on dialects with arrays.
+// No values render true.
+func NotIn(col string, values ...any) Expr {
+	return inExpr{col: col, values: listOf(renderedArgs(values)), not: true}
+}
+
+func (e inExpr) appendExpr(qb *QueryBuilder, b *buffer) {
+	if listLen(e.values) == 0 {
+		b.write(qb.dialect.Bool(e.not))
+		return
+	}
+	b.write(e.col)
+	if _, ok := e.values.([]any); !ok && qb.dialect.arrays {
+		if e.not {
+			b.write(" <> ALL(")
+		} else {
+			b.write(" = ANY(")
+		}
+		b.arg(e.values)
+		b.writeByte(')')
+		return
+	}
+	if e.not {
+		b.write(" NOT IN (")
+	} else {
+		b.write(" IN (")
+	}
+	b.expand(e.values)
+	b.writeByte(')')
+}
+
+func (inExpr) empty() bool {
+	return false
+}
+
+// listOf returns the single slice of values, or values themselves
+func listOf(values []any) any {
+	if len(values) == 1 && isList(values[0]) {
+		return values[0]
+	}
+	return values
+}
+
+type betweenExpr struct {
+	col    string
+	lo, hi any
+}
+
+// Between renders col BETWEEN lo AND hi.
+func Between(col string, lo, hi any) Expr {
+	return betweenExpr{col, renderedArg(lo), renderedArg(hi)}
+}
+
+func (e betweenExpr) appendExpr(qb *QueryBuilder, b *buffer) {
+	b.write(e.col)
+	b.write(" BETWEEN ")
+	b.arg(e.lo)
+	b.write(" AND ")
+	b.arg(e.hi)
+}
+
+func (betweenExpr) empty() bool {
+	return false
+}
+
+type nullExpr struct {
+	col string
+	op  string
+}
+
+// IsNull renders col IS NULL.
+func IsNull(col string) Expr {
+	return nullExpr{col, " IS NULL"}
+}
+
+// IsNotNull renders col IS NOT NULL.
+func IsNotNull(col string) Expr {
+	return nullExpr{col, " IS NOT NULL"}
+}
+
+func (e nullExpr) appendExpr(_ *QueryBuilder, b *buffer) {
+	b.write(e.col)
+	b.write(e.op)
+}
+
+func (nullExpr) empty() bool {
+	return false
+}
+
+type rawExpr struct {
+	sql  string
+	args []any
+}
+
+// Raw renders sql with '?' placeholders bound to args like Where does.
+func Raw(sql string, args ...any) Expr {
+	return rawExpr{sql, renderedArgs(args)}
+}
+
+func (e rawExpr) appendExpr(qb *QueryBuilder, b *buffer) {
+	b.bind(e.sql, e.args)
+}
+
+func (e rawExpr) empty() bool {
+	return e.sql == ""
+}
+
+type groupExpr struct {
+	op    string
+	exprs []Expr
+}
+
+// And joins exprs with AND. Nil and empty expressions are skipped, so a group of optional filters
+// collapses away when none of them is set.
+func And(exprs ...Expr) Expr {
+	return groupExpr{" AND ", exprs}
+}
+
+// Or joins exprs with OR, skipping nil and empty expressions like And.
+func Or(exprs ...Expr) Expr {
+	return groupExpr{" OR ", exprs}
+}
+
+func (e groupExpr) appendExpr(qb *QueryBuilder, b *buffer) {
+	wrap := e.size() > 1
+	first := true
+	for _, x := range e.exprs {
+		if isEmpty(x) {
+			continue
+		}
+		if !first {
+			b.write(e.op)
+		}
+		first = false
+		if wrap && needsParens(x) {
+			b.writeByte('(')
+			x.appendExpr(qb, b)
+			b.writeByte(')')
+		} else {
+			x.appendExpr(qb, b)
+		}
+	}
+}
+
+func (e groupExpr) empty() bool {
+	return e.size() == 0
+}
+
+// size returns the number of non empty expressions of the group
+func (e groupExpr) size() int {
+	n := 0
+	for _, x := range e.exprs {
+		if !isEmpty(x) {
+			n++
+		}
+	}
+	return n
+}
+
+type notExpr struct {
+	expr Expr
+}
+
+// Not renders NOT (expr), it is empty when expr is.
+func Not(expr Expr) Expr {
+	return notExpr{expr}
+}
+
+func (e notExpr) appendExpr(qb *QueryBuilder, b *buffer) {
+	b.write("NOT (")
+	e.expr.appendExpr(qb, b)
+	b.writeByte(')')
+}
+
+func (e notExpr) empty() bool {
+	return isEmpty(e.expr)
+}
+
+func isEmpty(e Expr) bool {
+	return e == nil || e.empty()
+}
+
+// needsParens reports whether e has to be parenthesized inside a group of several expressions
+func needsParens(e Expr) bool {
+	switch x := e.(type) {
+	case groupExpr:
+		return x.size() > 1
+	case rawExpr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestWhereExpr(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").
+		From("users").
+		Where("deleted_at IS NULL").
+		WhereExpr(sqb.And(
+			sqb.Eq("status", "active"),
+			sqb.Or(
+				sqb.Gt("age", 18),
+				sqb.Between("score", 10, 20),
+				sqb.Raw("role = ? OR role = ?", "admin", "owner"),
+			),
+			sqb.Not(sqb.In("country", "RU", "BY")),
+			sqb.IsNotNull("email"),
+			sqb.Like("name", "a%"),
+		)).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (deleted_at IS NULL) AND ("+
+		"status = $1 AND (age > $2 OR score BETWEEN $3 AND $4 OR (role = $5 OR role = $6)) "+
+		"AND NOT (country IN ($7, $8)) AND email IS NOT NULL AND name LIKE $9)", sql)
+	assert.Equal(t, []any{"active", 18, 10, 20, "admin", "owner", "RU", "BY", "a%"}, args)
+}
+
+func TestWhereExpr_Collapse(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	var name sqb.Expr
+	sql, args := qb.Select("id").
+		From("users").
+		WhereExpr(sqb.And(name, sqb.Or(), sqb.Not(sqb.And()))).
+		WhereExpr(sqb.And(sqb.Or(nil, sqb.Eq("id", 1)))).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (id = $1)", sql)
+	assert.Equal(t, []any{1}, args)
+
+	qb.Reset()
+	sql, _ = qb.Select("id").From("users").WhereExpr(sqb.And()).Sql()
+	assert.Equal(t, "SELECT id FROM users", sql)
+}
+
+func TestIn_Empty(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").From("users").WhereExpr(sqb.In("id")).Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (FALSE)", sql)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,112 @@
+package sqb
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/xakepp35/pkg/src/pkg/types"
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// ErrFilter is returned for filter documents with unsupported operators or values
+var ErrFilter = errors.New("invalid filter")
+
+// FromFilter translates a filter document received from a client into an expression for WhereExpr:
+//
+//	{"status": {"in": ["new", "paid"]}, "created_at": {"gte": "2024-01-01T00:00:00Z"}, "owner": 7}
+//
+// Fields are mapped to columns by schema, a plain value is a shorthand for eq. The operators are
+// eq, ne, in, gte, lte, like and is_null, which takes a bool. Values are always bound as args,
+// types.Time ones as time.Time. Fields and operators are rendered sorted, so equal filters give equal queries.
+// Unknown fields fail with ErrColumn and unknown operators or unfit values with ErrFilter, both InvalidArgument.
+func FromFilter(doc types.Document, schema Allowlist) (Expr, error) {
+	exprs := make([]Expr, 0, len(doc))
+	for _, field := range slices.Sorted(maps.Keys(doc)) {
+		col, err := schema.Column(field)
+		if err != nil {
+			return nil, err
+		}
+		ops, ok := doc[field].(types.Document)
+		if !ok {
+			ops = types.Document{"eq": doc[field]}
+		}
+		for _, op := range slices.Sorted(maps.Keys(ops)) {
+			e, err := filterExpr(col, op, ops[op])
+			if err != nil {
+				return nil, xerrors.Err(err).
+					Str("field", field).
+					Str("op", op).
+					Proto(codes.InvalidArgument)
+			}
+			exprs = append(exprs, e)
+		}
+	}
+	return And(exprs...), nil
+}
+
+// FromFilterStruct translates a filter received as types.Struct like FromFilter.
+func FromFilterStruct(s *types.Struct, schema Allowlist) (Expr, error) {
+	return FromFilter(s.AsMap(), schema)
+}
+
+func filterExpr(col, op string, value any) (Expr, error) {
+	if op == "in" {
+		values, ok := value.([]any)
+		if !ok {
+			return nil, ErrFilter
+		}
+		for i, v := range values {
+			if values[i], ok = filterValue(v); !ok {
+				return nil, ErrFilter
+			}
+		}
+		return In(col, values...), nil
+	}
+	if op == "is_null" {
+		isNull, ok := value.(bool)
+		if !ok {
+			return nil, ErrFilter
+		}
+		if isNull {
+			return IsNull(col), nil
+		}
+		return IsNotNull(col), nil
+	}
+	v, ok := filterValue(value)
+	if !ok {
+		return nil, ErrFilter
+	}
+	switch op {
+	case "eq":
+		return Eq(col, v), nil
+	case "ne":
+		return Ne(col, v), nil
+	case "gte":
+		return Ge(col, v), nil
+	case "lte":
+		return Le(col, v), nil
+	case "like":
+		if pattern, ok := v.(string); ok {
+			return Like(col, pattern), nil
+		}
+	}
+	return nil, ErrFilter
+}
+
+// filterValue returns the arg bound for a scalar value of a filter document
+func filterValue(value any) (any, bool) {
+	switch v := value.(type) {
+	case *types.Time:
+		if v == nil {
+			return nil, false
+		}
+		return v.AsTime(), true
+	case string, bool, float64, float32, int, int64, int32, uint, uint64, uint32, time.Time:
+		return v, true
+	}
+	return nil, false
+}
@@ -0,0 +1,72 @@
+package sqb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xakepp35/pkg/sqb"
+	"github.com/xakepp35/pkg/src/pkg/types"
+)
+
+var filterSchema = sqb.Allowlist{
+	"status":     "o.status",
+	"created_at": "o.created_at",
+	"owner":      "o.owner_id",
+	"note":       "o.note",
+	"deleted_at": "o.deleted_at",
+}
+
+func TestFromFilter(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter, err := sqb.FromFilterStruct(types.NewStruct(types.Document{
+		"status":     types.Document{"in": []any{"new", "paid"}, "ne": "void"},
+		"created_at": types.Document{"gte": since, "lte": "2024-02-01T00:00:00Z"},
+		"owner":      7,
+		"note":       types.Document{"like": "%gift%"},
+		"deleted_at": types.Document{"is_null": true},
+	}), filterSchema)
+	require.NoError(t, err)
+
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("o.id").From("orders o").WhereExpr(filter).Sql()
+	assert.Equal(t, "SELECT o.id FROM orders o WHERE (o.created_at >= $1 AND o.created_at <= $2 AND o.deleted_at IS NULL "+
+		"AND o.note LIKE $3 AND o.owner_id = $4 AND o.status IN ($5, $6) AND o.status <> $7)", sql)
+	assert.Equal(t, []any{since, "2024-02-01T00:00:00Z", "%gift%", int64(7), "new", "paid", "void"}, args)
+}
+
+func TestFromFilter_Empty(t *testing.T) {
+	filter, err := sqb.FromFilter(nil, filterSchema)
+	require.NoError(t, err)
+
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, _ := qb.Select("id").From("orders").WhereExpr(filter).Sql()
+	assert.Equal(t, "SELECT id FROM orders", sql)
+}
+
+func TestFromFilter_Invalid(t *testing.T) {
+	_, err := sqb.FromFilter(types.Document{"password": "x"}, filterSchema)
+	assert.ErrorIs(t, err, sqb.ErrColumn)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	for _, doc := range []types.Document{
+		{"status": types.Document{"regex": ".*"}},
+		{"status": types.Document{"in": "new"}},
+		{"status": types.Document{"eq": []any{"new"}}},
+		{"status": types.Document{"in": []any{types.Document{}}}},
+		{"owner": types.Document{"like": 7}},
+		{"deleted_at": types.Document{"is_null": "yes"}},
+	} {
+		_, err := sqb.FromFilter(doc, filterSchema)
+		assert.ErrorIs(t, err, sqb.ErrFilter, doc)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), doc)
+	}
+}
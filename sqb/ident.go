@@ -0,0 +1,38 @@
+package sqb
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// ErrColumn is returned for column names missing from an Allowlist
+var ErrColumn = errors.New("column is not allowed")
+
+// Allowlist maps the column names accepted from clients, e.g. in a sort query parameter,
+// to the trusted expressions written into the query.
+//
+//	sortable := sqb.Allowlist{"name": "u.name", "created": "u.created_at"}
+//	col, err := sortable.Column(c.Query("sort"))
+type Allowlist map[string]string
+
+// Column returns the expression allowed for name
+func (a Allowlist) Column(name string) (string, error) {
+	col, ok := a[name]
+	if !ok {
+		return "", xerrors.Err(ErrColumn).
+			Str("column", name).
+			Proto(codes.InvalidArgument)
+	}
+	return col, nil
+}
+
+// QuoteIdent quotes an identifier for Postgres and SQLite, escaping the quotes inside it,
+// so that any string can be written into the query as a column or table name.
+// Dots separate qualified names: "public.users" becomes "public"."users".
+// Dialect.Quote quotes for a specific dialect.
+func QuoteIdent(name string) string {
+	return Postgres.Quote(name)
+}
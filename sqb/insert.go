@@ -0,0 +1,87 @@
+package sqb
+
+// Insert starts an INSERT statement into the specified table.
+// Its clauses are rendered in the order INSERT, Columns, Values, OnConflict, Do..., Returning.
+func (qb *QueryBuilder) Insert(table string) *QueryBuilder {
+	qb.stmt = 'I'
+	qb.table = table
+	return qb
+}
+
+// Columns lists the columns the following Values rows are inserted into.
+func (qb *QueryBuilder) Columns(columns ...string) *QueryBuilder {
+	qb.columns = len(columns)
+	qb.cols.reset()
+	qb.cols.parenList(columns)
+	return qb
+}
+
+// Values adds one row of values, it may be called repeatedly to insert many rows.
+// It panics when the number of values does not match the preceding Columns.
+func (qb *QueryBuilder) Values(values ...any) *QueryBuilder {
+	if qb.columns > 0 && len(values) != qb.columns {
+		panic("number of values does not match columns")
+	}
+	if qb.rows == 0 {
+		qb.values.write("VALUES (")
+	} else {
+		qb.values.comma()
+		qb.values.writeByte('(')
+	}
+	for i, v := range values {
+		if i > 0 {
+			qb.values.write(", ")
+		}
+		qb.values.arg(v)
+	}
+	qb.values.write(") ")
+	qb.rows++
+	return qb
+}
+
+// OnConflict adds an ON CONFLICT clause with the specified conflict target columns,
+// no columns leave the target out. Follow it with DoNothing or DoUpdateSet.
+func (qb *QueryBuilder) OnConflict(columns ...string) *QueryBuilder {
+	qb.conflict.reset()
+	qb.conflict.write("ON CONFLICT ")
+	if len(columns) > 0 {
+		qb.conflict.parenList(columns)
+	}
+	return qb
+}
+
+// DoNothing completes OnConflict with DO NOTHING.
+func (qb *QueryBuilder) DoNothing() *QueryBuilder {
+	qb.conflict.write("DO NOTHING ")
+	return qb
+}
+
+// DoUpdateSet completes OnConflict with DO UPDATE SET col = EXCLUDED.col for every specified column.
+func (qb *QueryBuilder) DoUpdateSet(columns ...string) *QueryBuilder {
+	qb.conflict.write("DO UPDATE SET ")
+	for i, col := range columns {
+		if i > 0 {
+			qb.conflict.write(", ")
+		}
+		qb.conflict.write(col)
+		qb.conflict.write(" = EXCLUDED.")
+		qb.conflict.write(col)
+	}
+	qb.conflict.writeByte(' ')
+	return qb
+}
+
+// Returning adds the specified columns to the RETURNING clause.
+// It panics for dialects without RETURNING.
+func (qb *QueryBuilder) Returning(columns ...string) *QueryBuilder {
+	if !qb.dialect.returning {
+		panic("RETURNING is not supported by " + qb.dialect.name)
+	}
+	if len(qb.returning.sql) == 0 {
+		qb.returning.write("RETURNING ")
+	} else {
+		qb.returning.comma()
+	}
+	qb.returning.list(columns)
+	return qb
+}
@@ -0,0 +1,51 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestInsert(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Insert("users").
+		Columns("id", "name").
+		Values(1, "alice").
+		Values(2, "bob").
+		Returning("id").
+		Sql()
+	assert.Equal(t, "INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4) RETURNING id", sql)
+	assert.Equal(t, []any{1, "alice", 2, "bob"}, args)
+}
+
+func TestInsert_OnConflict(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Insert("users").
+		Columns("id", "name", "email").
+		Values(1, "alice", "a@x").
+		OnConflict("id").
+		DoUpdateSet("name", "email").
+		Sql()
+	assert.Equal(t, "INSERT INTO users (id, name, email) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email", sql)
+	assert.Len(t, args, 3)
+
+	qb.Reset()
+	sql, _ = qb.Insert("users").Columns("id").Values(1).OnConflict().DoNothing().Sql()
+	assert.Equal(t, "INSERT INTO users (id) VALUES ($1) ON CONFLICT DO NOTHING", sql)
+}
+
+func TestInsert_ValuesMismatch(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.Insert("users").Columns("id", "name").Values(1)
+	})
+}
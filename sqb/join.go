@@ -0,0 +1,49 @@
+package sqb
+
+// Join adds an INNER JOIN of table on the specified condition.
+// Like every clause, joins are rendered in their place after FROM whenever they are called.
+func (qb *QueryBuilder) Join(table, on string, args ...any) *QueryBuilder {
+	return qb.join("JOIN ", table, on, args)
+}
+
+// LeftJoin adds a LEFT JOIN of table on the specified condition.
+func (qb *QueryBuilder) LeftJoin(table, on string, args ...any) *QueryBuilder {
+	return qb.join("LEFT JOIN ", table, on, args)
+}
+
+// RightJoin adds a RIGHT JOIN of table on the specified condition.
+func (qb *QueryBuilder) RightJoin(table, on string, args ...any) *QueryBuilder {
+	return qb.join("RIGHT JOIN ", table, on, args)
+}
+
+// FullJoin adds a FULL JOIN of table on the specified condition.
+func (qb *QueryBuilder) FullJoin(table, on string, args ...any) *QueryBuilder {
+	return qb.join("FULL JOIN ", table, on, args)
+}
+
+// CrossJoin adds a CROSS JOIN of table, which has no condition.
+func (qb *QueryBuilder) CrossJoin(table string, args ...any) *QueryBuilder {
+	return qb.join("CROSS JOIN ", table, "", args)
+}
+
+// JoinLateral adds a JOIN LATERAL of a subquery like "(SELECT ... LIMIT ?) AS t" on the specified condition.
+// args bind the placeholders of the subquery first and then the ones of the condition.
+func (qb *QueryBuilder) JoinLateral(subquery, on string, args ...any) *QueryBuilder {
+	return qb.join("JOIN LATERAL ", subquery, on, args)
+}
+
+// LeftJoinLateral adds a LEFT JOIN LATERAL of a subquery on the specified condition, usually "true".
+func (qb *QueryBuilder) LeftJoinLateral(subquery, on string, args ...any) *QueryBuilder {
+	return qb.join("LEFT JOIN LATERAL ", subquery, on, args)
+}
+
+func (qb *QueryBuilder) join(kind, table, on string, args []any) *QueryBuilder {
+	clause := table
+	if on != "" {
+		clause += " ON " + on
+	}
+	qb.joins.write(kind)
+	qb.joins.bind(clause, args)
+	qb.joins.writeByte(' ')
+	return qb
+}
@@ -0,0 +1,55 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestJoin(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("u.id", "o.total").
+		From("users u").
+		Join("orders o", "o.user_id = u.id AND o.status = ?", "paid").
+		LeftJoin("coupons c", "c.order_id = o.id").
+		Where("u.age > ?", 18).
+		Sql()
+	assert.Equal(t, "SELECT u.id, o.total FROM users u "+
+		"JOIN orders o ON o.user_id = u.id AND o.status = $1 "+
+		"LEFT JOIN coupons c ON c.order_id = o.id "+
+		"WHERE (u.age > $2)", sql)
+	assert.Equal(t, []any{"paid", 18}, args)
+}
+
+func TestJoin_AfterWhere(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("u.id").
+		From("users u").
+		Where("u.age > ?", 18).
+		FullJoin("teams t", "t.id = u.team_id AND t.region = ?", "eu").
+		LeftJoinLateral("(SELECT id FROM orders WHERE user_id = u.id LIMIT ?) AS o", "true", 3).
+		CrossJoin("settings").
+		Limit(10).
+		Sql()
+	assert.Equal(t, "SELECT u.id FROM users u "+
+		"FULL JOIN teams t ON t.id = u.team_id AND t.region = $1 "+
+		"LEFT JOIN LATERAL (SELECT id FROM orders WHERE user_id = u.id LIMIT $2) AS o ON true "+
+		"CROSS JOIN settings "+
+		"WHERE (u.age > $3) LIMIT 10", sql)
+	assert.Equal(t, []any{"eu", 3, 18}, args)
+}
+
+func TestJoin_Mismatch(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.From("a").Join("b", "b.id = a.id", 1)
+	})
+}
@@ -0,0 +1,39 @@
+package sqb
+
+import "reflect"
+
+type list struct {
+	values any
+}
+
+// List wraps a slice so that its '?' placeholder expands into one placeholder per element:
+//
+//	qb.Where("id IN (?)", sqb.List(ids)) // id IN ($1, $2, $3)
+//
+// An empty slice expands into NULL, which matches nothing in IN (?). Since NOT IN (NULL) matches nothing either,
+// prefer NotIn for negations. It panics unless slice is a slice or an array.
+func List(slice any) any {
+	if !isList(slice) {
+		panic("sqb.List needs a slice or an array")
+	}
+	return list{slice}
+}
+
+// isList reports whether v is a slice or an array other than []byte, which binds as a single value
+func isList(v any) bool {
+	if _, ok := v.([]byte); ok || v == nil {
+		return false
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+func listLen(v any) int {
+	if vs, ok := v.([]any); ok {
+		return len(vs)
+	}
+	return reflect.ValueOf(v).Len()
+}
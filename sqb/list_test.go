@@ -0,0 +1,73 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestIn_Slice(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	ids := []int64{1, 2, 3}
+	sql, args := qb.Select("id").
+		From("users").
+		WhereExpr(sqb.And(
+			sqb.In("id", ids),
+			sqb.NotIn("role", []string{"bot"}),
+			sqb.In("status", "new", "active"),
+			sqb.In("token", []byte("x")),
+		)).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (id = ANY($1) AND role <> ALL($2) "+
+		"AND status IN ($3, $4) AND token IN ($5))", sql)
+	assert.Equal(t, []any{ids, []string{"bot"}, "new", "active", []byte("x")}, args)
+}
+
+func TestIn_Expand(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.SetDialect(sqb.SQLite).
+		Select("id").
+		From("users").
+		WhereExpr(sqb.In("id", []int64{1, 2})).
+		WhereExpr(sqb.NotIn("role", [1]string{"bot"})).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (id IN (?, ?)) AND (role NOT IN (?))", sql)
+	assert.Equal(t, []any{int64(1), int64(2), "bot"}, args)
+}
+
+func TestIn_EmptySlice(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").
+		From("users").
+		WhereExpr(sqb.In("id", []int64{})).
+		WhereExpr(sqb.NotIn("id", []int64(nil))).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (FALSE) AND (TRUE)", sql)
+	assert.Empty(t, args)
+}
+
+func TestList(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").
+		From("users").
+		Where("id IN (?) AND age > ?", sqb.List([]int{7, 8, 9}), 18).
+		Where("role IN (?)", sqb.List([]string{})).
+		Where("id = ANY(?)", []int{1, 2}).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (id IN ($1, $2, $3) AND age > $4) AND (role IN (NULL)) AND (id = ANY($5))", sql)
+	assert.Equal(t, []any{7, 8, 9, 18, []int{1, 2}}, args)
+
+	assert.Panics(t, func() {
+		sqb.List(42)
+	})
+}
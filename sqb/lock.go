@@ -0,0 +1,50 @@
+package sqb
+
+// ForUpdate locks the selected rows against updates and deletes by other transactions.
+// The locking clause is rendered at the end of the query whenever it is called.
+// It panics for dialects without row locking.
+func (qb *QueryBuilder) ForUpdate() *QueryBuilder {
+	return qb.lockRows("FOR UPDATE")
+}
+
+// ForNoKeyUpdate locks the selected rows like ForUpdate, but lets other transactions take FOR KEY SHARE locks.
+// It panics for dialects other than Postgres.
+func (qb *QueryBuilder) ForNoKeyUpdate() *QueryBuilder {
+	if !qb.dialect.keyLocks {
+		panic("FOR NO KEY UPDATE is not supported by " + qb.dialect.name)
+	}
+	return qb.lockRows("FOR NO KEY UPDATE")
+}
+
+// ForShare locks the selected rows against updates, allowing other shared locks.
+func (qb *QueryBuilder) ForShare() *QueryBuilder {
+	return qb.lockRows("FOR SHARE")
+}
+
+// Of restricts the locking clause to the specified tables or aliases.
+func (qb *QueryBuilder) Of(tables ...string) *QueryBuilder {
+	qb.lockOf = append(qb.lockOf, tables...)
+	return qb
+}
+
+// NoWait makes the query fail instead of waiting for rows locked by other transactions.
+func (qb *QueryBuilder) NoWait() *QueryBuilder {
+	qb.lockWait = "NOWAIT"
+	return qb
+}
+
+// SkipLocked skips the rows locked by other transactions, e.g. to let queue workers take different jobs:
+//
+//	qb.Select("id").From("jobs").Where("status = ?", "new").Limit(10).ForUpdate().SkipLocked()
+func (qb *QueryBuilder) SkipLocked() *QueryBuilder {
+	qb.lockWait = "SKIP LOCKED"
+	return qb
+}
+
+func (qb *QueryBuilder) lockRows(strength string) *QueryBuilder {
+	if !qb.dialect.locking {
+		panic("row locking is not supported by " + qb.dialect.name)
+	}
+	qb.lock = strength
+	return qb
+}
@@ -0,0 +1,70 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestForUpdate_SkipLocked(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.ForUpdate().
+		SkipLocked().
+		Select("id", "payload").
+		From("jobs j").
+		Where("status = ?", "new").
+		OrderBy("id", sqb.Asc).
+		SelectExpr("count(*) OVER ()").
+		Of("j").
+		Limit(10).
+		Sql()
+	assert.Equal(t, "SELECT id, payload, count(*) OVER () FROM jobs j WHERE (status = $1) "+
+		"ORDER BY id ASC LIMIT 10 FOR UPDATE OF j SKIP LOCKED", sql)
+	assert.Equal(t, []any{"new"}, args)
+}
+
+func TestForShare_NoWait(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, _ := qb.Select("id").From("accounts").ForNoKeyUpdate().ForShare().NoWait().Sql()
+	assert.Equal(t, "SELECT id FROM accounts FOR SHARE NOWAIT", sql)
+}
+
+func TestSelectExpr_Args(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").
+		From("items").
+		Where("owner = ?", 7).
+		SelectExpr("price * ? AS total", 1.2).
+		LeftJoin("prices p", "p.item = items.id AND p.currency = ?", "EUR").
+		Sql()
+	assert.Equal(t, "SELECT id, price * $1 AS total FROM items LEFT JOIN prices p ON p.item = items.id AND p.currency = $2 "+
+		"WHERE (owner = $3)", sql)
+	assert.Equal(t, []any{1.2, "EUR", 7}, args)
+}
+
+func TestForUpdate_Unsupported(t *testing.T) {
+	qb := sqb.GetBuilder().SetDialect(sqb.SQLite)
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() { qb.ForUpdate() })
+
+	assert.Panics(t, func() { qb.ForNoKeyUpdate() })
+
+	qb.Reset()
+	qb.SetDialect(sqb.MySQL)
+	assert.Panics(t, func() { qb.ForNoKeyUpdate() })
+	sql, _ := qb.Select("id").From("t").ForUpdate().Sql()
+	assert.Equal(t, "SELECT id FROM t FOR UPDATE", sql)
+
+	qb.Reset()
+	qb.ForUpdate()
+	assert.Panics(t, func() { qb.SetDialect(sqb.SQLite) })
+}
@@ -0,0 +1,102 @@
+package sqb
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// Direction is the sort direction of an ORDER BY column.
+type Direction string
+
+const (
+	Asc            Direction = "ASC"
+	Desc           Direction = "DESC"
+	AscNullsFirst  Direction = "ASC NULLS FIRST"
+	AscNullsLast   Direction = "ASC NULLS LAST"
+	DescNullsFirst Direction = "DESC NULLS FIRST"
+	DescNullsLast  Direction = "DESC NULLS LAST"
+)
+
+// ErrDirection is returned for sort directions other than asc or desc, optionally followed by nulls first or last
+var ErrDirection = errors.New("invalid sort direction")
+
+// ParseDirection parses a sort direction received from a client like "desc" or "asc_nulls_last",
+// ignoring case. An empty string is Asc.
+func ParseDirection(s string) (Direction, error) {
+	if s == "" {
+		return Asc, nil
+	}
+	dir := Direction(strings.ToUpper(strings.Join(strings.Fields(strings.ReplaceAll(s, "_", " ")), " ")))
+	if !dir.Valid() {
+		return "", xerrors.Err(ErrDirection).
+			Str("direction", s).
+			Proto(codes.InvalidArgument)
+	}
+	return dir, nil
+}
+
+// Valid reports whether d is one of the defined directions
+func (d Direction) Valid() bool {
+	switch d {
+	case Asc, Desc, AscNullsFirst, AscNullsLast, DescNullsFirst, DescNullsLast:
+		return true
+	}
+	return false
+}
+
+// OrderBy adds col with the specified direction to the ORDER BY clause, an empty direction leaves it out.
+// col is written as is, pass columns received from clients through an Allowlist or QuoteIdent.
+// It panics for directions other than the defined ones.
+func (qb *QueryBuilder) OrderBy(col string, dir Direction) *QueryBuilder {
+	if dir != "" && !dir.Valid() {
+		panic("invalid sort direction")
+	}
+	if len(qb.orderBy.sql) == 0 {
+		qb.orderBy.write("ORDER BY ")
+	} else {
+		qb.orderBy.comma()
+	}
+	qb.orderBy.write(col)
+	if dir != "" {
+		qb.orderBy.writeByte(' ')
+		qb.orderBy.write(string(dir))
+	}
+	qb.orderBy.writeByte(' ')
+	return qb
+}
+
+// GroupBy adds the specified columns to the GROUP BY clause.
+func (qb *QueryBuilder) GroupBy(columns ...string) *QueryBuilder {
+	if len(qb.groupBy.sql) == 0 {
+		qb.groupBy.write("GROUP BY ")
+	} else {
+		qb.groupBy.comma()
+	}
+	qb.groupBy.list(columns)
+	return qb
+}
+
+// Having adds a condition to the HAVING clause with '?' placeholders like Where,
+// conditions of several calls are combined with AND.
+func (qb *QueryBuilder) Having(clause string, args ...any) *QueryBuilder {
+	if len(qb.having.sql) == 0 {
+		qb.having.write("HAVING (")
+	} else {
+		qb.having.write("AND (")
+	}
+	qb.having.bind(clause, args)
+	qb.having.write(") ")
+	return qb
+}
+
+// DistinctOn turns the SELECT clause into SELECT DISTINCT ON (columns).
+func (qb *QueryBuilder) DistinctOn(columns ...string) *QueryBuilder {
+	qb.distinct.reset()
+	qb.distinct.write("DISTINCT ON ")
+	qb.distinct.parenList(columns)
+	return qb
+}
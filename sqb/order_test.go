@@ -0,0 +1,80 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestOrderBy(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.DistinctOn("user_id").
+		Select("user_id", "count(*)").
+		From("orders").
+		Where("total > ?", 100).
+		GroupBy("user_id").
+		GroupBy("day").
+		Having("count(*) > ?", 2).
+		Having("sum(total) < ?", 1000).
+		OrderBy("user_id", sqb.Asc).
+		OrderBy("day", sqb.DescNullsLast).
+		OrderBy("id", "").
+		Limit(5).
+		Sql()
+	assert.Equal(t, "SELECT DISTINCT ON (user_id) user_id, count(*) FROM orders WHERE (total > $1) "+
+		"GROUP BY user_id, day HAVING (count(*) > $2) AND (sum(total) < $3) "+
+		"ORDER BY user_id ASC, day DESC NULLS LAST, id LIMIT 5", sql)
+	assert.Equal(t, []any{100, 2, 1000}, args)
+}
+
+func TestOrderBy_InvalidDirection(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.OrderBy("id", "; DROP TABLE users")
+	})
+}
+
+func TestParseDirection(t *testing.T) {
+	for in, want := range map[string]sqb.Direction{
+		"":                  sqb.Asc,
+		"asc":               sqb.Asc,
+		"DESC":              sqb.Desc,
+		"desc_nulls_last":   sqb.DescNullsLast,
+		" asc  nulls first": sqb.AscNullsFirst,
+	} {
+		dir, err := sqb.ParseDirection(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, dir, in)
+	}
+
+	_, err := sqb.ParseDirection("desc; DROP TABLE users")
+	assert.ErrorIs(t, err, sqb.ErrDirection)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAllowlist(t *testing.T) {
+	sortable := sqb.Allowlist{"name": "u.name"}
+
+	col, err := sortable.Column("name")
+	require.NoError(t, err)
+	assert.Equal(t, "u.name", col)
+
+	_, err = sortable.Column("password")
+	assert.ErrorIs(t, err, sqb.ErrColumn)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestQuoteIdent(t *testing.T) {
+	assert.Equal(t, `"users"`, sqb.QuoteIdent("users"))
+	assert.Equal(t, `"public"."users"`, sqb.QuoteIdent("public.users"))
+	assert.Equal(t, `"a""; DROP TABLE users; --"`, sqb.QuoteIdent(`a"; DROP TABLE users; --`))
+}
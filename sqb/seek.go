@@ -0,0 +1,146 @@
+package sqb
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/grpc/codes"
+
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// Cursor holds the values of the order columns of the last row of a page in keyset pagination
+type Cursor []any
+
+// ErrCursor is returned for page tokens that are malformed or were not issued with the same key
+var ErrCursor = errors.New("invalid cursor")
+
+// Seek adds keyset pagination: the row value comparison (cols...) > (cursor...) for Asc, or < for Desc,
+// the matching ORDER BY and LIMIT limit+1, so that the extra row tells whether a next page exists.
+// An empty cursor selects the first page. The comparison is combined with the other conditions by AND,
+// conditions joined by Or are parenthesized first.
+// It panics unless dir is Asc or Desc and limit is positive, or when the cursor does not match the columns.
+func (qb *QueryBuilder) Seek(cursor Cursor, dir Direction, limit int, cols ...string) *QueryBuilder {
+	if dir != Asc && dir != Desc {
+		panic("seek direction must be ASC or DESC")
+	}
+	if limit <= 0 {
+		panic("seek limit must be positive")
+	}
+	if len(cursor) > 0 {
+		if len(cursor) != len(cols) {
+			panic("number of cursor values does not match columns")
+		}
+		if qb.ored {
+			// parenthesize the conditions so far, so that the comparison restricts all of them
+			qb.where.sql = slices.Insert(qb.where.sql, len("WHERE "), '(')
+			qb.where.sql = append(qb.where.sql[:len(qb.where.sql)-1], ") "...)
+			qb.ored = false
+		}
+		qb.operator = 'A'
+		qb.whereStart()
+		qb.where.parenList(cols)
+		if dir == Asc {
+			qb.where.write("> (")
+		} else {
+			qb.where.write("< (")
+		}
+		for i, v := range cursor {
+			if i > 0 {
+				qb.where.write(", ")
+			}
+			qb.where.arg(v)
+		}
+		qb.where.write(")) ")
+	}
+	for _, col := range cols {
+		qb.OrderBy(col, dir)
+	}
+	return qb.Limit(limit + 1)
+}
+
+// sumSize is the length of the HMAC-SHA256 tag kept in tokens, truncated to 128 bits
+const sumSize = 16
+
+// CursorCodec turns cursors into opaque page tokens like next_page_token and back.
+// Tokens are signed with HMAC-SHA256 keyed with the codec key, so that modified or forged tokens are rejected.
+// They are not encrypted: keep anything secret out of cursors.
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec returns a codec keyed with key, which has to be kept secret
+func NewCursorCodec(key string) CursorCodec {
+	return CursorCodec{
+		key: []byte(key),
+	}
+}
+
+// Encode returns the token of cursor, an empty cursor has an empty token
+func (c CursorCodec) Encode(cursor Cursor) (string, error) {
+	if len(cursor) == 0 {
+		return "", nil
+	}
+	payload, err := msgpack.Marshal([]any(cursor))
+	if err != nil {
+		return "", err
+	}
+	payload = append(payload, c.sum(payload)...)
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// Decode returns the cursor of token, an empty token is the empty cursor of the first page.
+// Integers are decoded as int64 or uint64 and floats as float64.
+func (c CursorCodec) Decode(token string) (Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= sumSize {
+		return nil, errCursor("malformed token")
+	}
+	payload, sum := raw[:len(raw)-sumSize], raw[len(raw)-sumSize:]
+	if !hmac.Equal(c.sum(payload), sum) {
+		return nil, errCursor("signature mismatch")
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(payload))
+	dec.UseLooseInterfaceDecoding(true)
+	var values []any
+	if err := dec.Decode(&values); err != nil {
+		return nil, errCursor("malformed payload")
+	}
+	return values, nil
+}
+
+// sum returns the truncated HMAC of payload
+func (c CursorCodec) sum(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)[:sumSize]
+}
+
+// NextPage trims the extra row fetched by Seek and returns the token of the next page,
+// which is empty on the last page. cursor returns the order column values of a row.
+// It panics unless limit is positive, like Seek.
+func NextPage[T any](codec CursorCodec, rows []T, limit int, cursor func(T) Cursor) ([]T, string, error) {
+	if limit <= 0 {
+		panic("seek limit must be positive")
+	}
+	if len(rows) <= limit {
+		return rows, "", nil
+	}
+	rows = rows[:limit]
+	token, err := codec.Encode(cursor(rows[limit-1]))
+	return rows, token, err
+}
+
+func errCursor(reason string) error {
+	return xerrors.Err(ErrCursor).
+		Str("reason", reason).
+		Proto(codes.InvalidArgument)
+}
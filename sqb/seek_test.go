@@ -0,0 +1,124 @@
+package sqb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestSeek(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	sql, args := qb.Select("id", "created_at").
+		From("events").
+		Where("kind = ?", "login").
+		Seek(sqb.Cursor{at, 42}, sqb.Desc, 20, "created_at", "id").
+		Sql()
+	assert.Equal(t, "SELECT id, created_at FROM events WHERE (kind = $1) AND ((created_at, id) < ($2, $3)) "+
+		"ORDER BY created_at DESC, id DESC LIMIT 21", sql)
+	assert.Equal(t, []any{"login", at, 42}, args)
+
+	qb.Reset()
+	sql, args = qb.Select("id").From("events").Seek(nil, sqb.Asc, 10, "id").Sql()
+	assert.Equal(t, "SELECT id FROM events ORDER BY id ASC LIMIT 11", sql)
+	assert.Empty(t, args)
+}
+
+func TestSeek_Or(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").
+		From("t").
+		Where("a = ?", 1).Or().Where("b = ?", 2).
+		Or().
+		Seek(sqb.Cursor{5}, sqb.Asc, 10, "id").
+		Sql()
+	assert.Equal(t, "SELECT id FROM t WHERE ((a = $1) OR (b = $2)) AND ((id) > ($3)) ORDER BY id ASC LIMIT 11", sql)
+	assert.Equal(t, []any{1, 2, 5}, args)
+}
+
+func TestSeek_Panics(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.Seek(nil, sqb.DescNullsLast, 10, "id")
+	})
+	assert.Panics(t, func() {
+		qb.Seek(sqb.Cursor{1}, sqb.Asc, 10, "created_at", "id")
+	})
+	assert.Panics(t, func() {
+		qb.Seek(nil, sqb.Asc, 0, "id")
+	})
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := sqb.NewCursorCodec("secret")
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	token, err := codec.Encode(sqb.Cursor{at, 42, "abc"})
+	require.NoError(t, err)
+	assert.NotContains(t, token, "=")
+
+	cursor, err := codec.Decode(token)
+	require.NoError(t, err)
+	require.Len(t, cursor, 3)
+	assert.True(t, at.Equal(cursor[0].(time.Time)))
+	assert.Equal(t, int64(42), cursor[1])
+	assert.Equal(t, "abc", cursor[2])
+
+	cursor, err = codec.Decode("")
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+}
+
+func TestCursorCodec_Tampered(t *testing.T) {
+	codec := sqb.NewCursorCodec("secret")
+	token, err := codec.Encode(sqb.Cursor{42})
+	require.NoError(t, err)
+
+	_, err = sqb.NewCursorCodec("other").Decode(token)
+	assert.ErrorIs(t, err, sqb.ErrCursor)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	tampered := []byte(token)
+	tampered[0] ^= 1
+	_, err = codec.Decode(string(tampered))
+	assert.ErrorIs(t, err, sqb.ErrCursor)
+
+	_, err = codec.Decode(token[:len(token)-4])
+	assert.ErrorIs(t, err, sqb.ErrCursor)
+
+	_, err = codec.Decode("%%%")
+	assert.ErrorIs(t, err, sqb.ErrCursor)
+}
+
+func TestNextPage(t *testing.T) {
+	codec := sqb.NewCursorCodec("secret")
+	id := func(v int) sqb.Cursor { return sqb.Cursor{v} }
+
+	rows, token, err := sqb.NextPage(codec, []int{1, 2, 3}, 2, id)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, rows)
+	cursor, err := codec.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, sqb.Cursor{int64(2)}, cursor)
+
+	rows, token, err = sqb.NextPage(codec, []int{1, 2}, 2, id)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, rows)
+	assert.Empty(t, token)
+
+	assert.Panics(t, func() {
+		sqb.NextPage(codec, []int{1}, 0, id)
+	})
+}
@@ -1,17 +1,61 @@
 package sqb
 
 import (
-	"strconv"
-	"strings"
+	"slices"
 	"sync"
 )
 
+// QueryBuilder keeps every clause of the query apart and renders them in the canonical SQL order in Sql,
+// so the methods may be called in any order and a base query can be cloned and extended.
 type QueryBuilder struct {
-	query    strings.Builder
-	args     []any
-	operator byte
-	argCount int
-	hasWhere bool
+	clauses
+	state
+	out    buffer
+	lockOf []string
+}
+
+// state holds the rest of the builder, which is copied as is by Clone
+type state struct {
+	dialect   *Dialect
+	stmt      byte // 0 for SELECT, 'I' for INSERT, 'U' for UPDATE and 'D' for DELETE
+	table     string
+	operator  byte
+	ored      bool // the WHERE clause combines conditions with OR
+	columns   int
+	rows      int
+	limit     int
+	offset    int
+	lock      string
+	lockWait  string
+	fullScan  bool
+	recursive bool
+}
+
+// clauses holds the parts of the query with placeholder marks, each one starting with its keyword if it has one
+type clauses struct {
+	with      buffer
+	distinct  buffer
+	selects   buffer
+	cols      buffer
+	values    buffer
+	conflict  buffer
+	set       buffer
+	from      buffer
+	joins     buffer
+	where     buffer
+	groupBy   buffer
+	having    buffer
+	setOps    buffer
+	orderBy   buffer
+	returning buffer
+}
+
+// all returns every clause
+func (c *clauses) all() [15]*buffer {
+	return [...]*buffer{
+		&c.with, &c.distinct, &c.selects, &c.cols, &c.values, &c.conflict, &c.set, &c.from,
+		&c.joins, &c.where, &c.groupBy, &c.having, &c.setOps, &c.orderBy, &c.returning,
+	}
 }
 
 // builderPool is a sync.Pool that provides a pool of reusable QueryBuilder instances.
@@ -19,7 +63,10 @@ type QueryBuilder struct {
 var builderPool = sync.Pool{
 	New: func() any {
 		return &QueryBuilder{
-			args: make([]any, 0, 16),
+			out: buffer{
+				sql:  make([]byte, 0, 256),
+				args: make([]any, 0, 16),
+			},
 		}
 	},
 }
@@ -34,99 +81,132 @@ func GetBuilder() *QueryBuilder {
 
 // RealiseBuilder puts the provided QueryBuilder back into the pool, resetting its internal state first.
 func RealiseBuilder(qb *QueryBuilder) {
-	qb.query.Reset()
+	qb.Reset()
 	builderPool.Put(qb)
 }
 
 // Reset resets the QueryBuilder's internal state, clearing the query string and argument list.
 func (qb *QueryBuilder) Reset() {
-	qb.query.Reset()
-	qb.args = qb.args[:0]
-	qb.operator = 0
-	qb.argCount = 0
-	qb.hasWhere = false
+	for _, b := range qb.all() {
+		b.reset()
+	}
+	qb.out.reset()
+	qb.lockOf = qb.lockOf[:0]
+	qb.state = state{
+		dialect: defaultDialect.Load(),
+		limit:   -1,
+		offset:  -1,
+	}
 }
 
-// Select adds a SELECT clause to the query with the specified columns.
-func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
-	qb.query.WriteString("SELECT ")
-	for i, col := range columns {
-		if i > 0 {
-			qb.query.WriteString(", ")
+// owns reports whether b is one of the clauses of qb
+func (qb *QueryBuilder) owns(b *buffer) bool {
+	for _, c := range qb.all() {
+		if c == b {
+			return true
 		}
-		qb.query.WriteString(col)
 	}
-	qb.query.WriteByte(' ')
+	return b == &qb.out
+}
+
+// Clone returns a copy of the builder taken from the pool, e.g. to count the rows of a filtered query
+// and fetch a page of them:
+//
+//	base := sqb.GetBuilder().From("users").WhereExpr(filter)
+//	count := base.Clone().Select("count(*)")
+//	page := base.Select("id", "name").OrderBy("id", sqb.Asc).Limit(20)
+//
+// Both builders have to be released, the clone is independent of qb.
+func (qb *QueryBuilder) Clone() *QueryBuilder {
+	c := builderPool.Get().(*QueryBuilder) // released builders are reset
+	dst, src := c.all(), qb.all()
+	for i := range dst {
+		dst[i].append(src[i])
+	}
+	c.state = qb.state
+	c.lockOf = append(c.lockOf, qb.lockOf...)
+	return c
+}
+
+// Select adds a SELECT clause to the query with the specified columns.
+// Columns of later calls are added to the same select list.
+func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
+	qb.selectNext()
+	qb.selects.list(columns)
+	return qb
+}
+
+// SelectExpr adds an expression with '?' placeholders like Where to the select list,
+// e.g. SelectExpr("count(*) OVER ()") or SelectExpr("price * ? AS total", rate).
+func (qb *QueryBuilder) SelectExpr(expr string, args ...any) *QueryBuilder {
+	qb.selectNext()
+	qb.selects.bind(expr, args)
+	qb.selects.writeByte(' ')
 	return qb
 }
 
-// From adds a FROM clause to the query with the specified table name.
+// selectNext separates the next item of the select list
+func (qb *QueryBuilder) selectNext() {
+	if len(qb.selects.sql) > 0 {
+		qb.selects.comma()
+	}
+}
+
+// From sets the FROM clause of the query to the specified table name.
 func (qb *QueryBuilder) From(table string) *QueryBuilder {
-	qb.query.WriteString("FROM " + table + " ")
+	qb.from.reset()
+	qb.from.write("FROM ")
+	qb.from.write(table)
+	qb.from.writeByte(' ')
 	return qb
 }
 
-// Limit adds a LIMIT clause to the query with the specified limit value.
+// Limit sets the LIMIT of the query, a negative limit removes it.
 func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
-	qb.query.WriteString("LIMIT ")
-	qb.query.WriteString(strconv.Itoa(limit))
-	qb.query.WriteByte(' ')
+	qb.limit = limit
 	return qb
 }
 
-// Offset adds an OFFSET clause to the query with the specified offset value.
+// Offset sets the OFFSET of the query, a negative offset removes it.
+// Dialects that cannot OFFSET without LIMIT get an unbounded LIMIT when there is no Limit.
 func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
-	qb.query.WriteString("OFFSET ")
-	qb.query.WriteString(strconv.Itoa(offset))
-	qb.query.WriteByte(' ')
+	qb.offset = offset
 	return qb
 }
 
 // Where adds a WHERE clause to the query with the specified condition and arguments.
 // The condition should contain placeholders represented by '?' for the arguments.
-
+// A *QueryBuilder argument is embedded as a subquery, e.g. Where("id IN (?)", sub).
 func (qb *QueryBuilder) Where(clause string, args ...any) *QueryBuilder {
-	countArgs := len(args)
-	if countArgs > 0 {
-		if strings.Count(clause, "?") != countArgs {
-			panic("number of placeholders does not match args")
-		}
-
-		var newClause strings.Builder
-		newClause.Grow(len(clause) + countArgs*3)
-		placeholders := 1 + qb.argCount
-
-		for i := range len(clause) {
-			if clause[i] == '?' {
-				newClause.WriteByte('$')
-				newClause.WriteString(strconv.Itoa(placeholders))
-				placeholders++
-			} else {
-				newClause.WriteByte(clause[i])
-			}
-		}
+	qb.whereStart()
+	qb.where.bind(clause, args)
+	qb.where.write(") ")
+	return qb
+}
 
-		clause = newClause.String()
-		qb.argCount += countArgs
+// WhereExpr adds a condition built from expressions like And(Eq("a", 1), Or(...)) to the WHERE clause,
+// combined with the previous condition like Where. An empty expression adds nothing.
+func (qb *QueryBuilder) WhereExpr(e Expr) *QueryBuilder {
+	if isEmpty(e) {
+		return qb
 	}
+	qb.whereStart()
+	e.appendExpr(qb, &qb.where)
+	qb.where.write(") ")
+	return qb
+}
 
-	if !qb.hasWhere {
-		qb.hasWhere = true
-		qb.query.WriteString("WHERE (")
+// whereStart opens the next parenthesized condition of the WHERE clause
+func (qb *QueryBuilder) whereStart() {
+	if len(qb.where.sql) == 0 {
+		qb.where.write("WHERE (")
+	} else if qb.operator == 'O' {
+		qb.where.write("OR (")
+		qb.ored = true
 	} else {
-		if qb.operator == 'O' {
-			qb.query.WriteString("OR (")
-		} else {
-			qb.query.WriteString("AND (")
-		}
+		qb.where.write("AND (")
 	}
-
-	qb.query.WriteString(clause)
-	qb.query.WriteString(") ")
-
-	qb.args = append(qb.args, args...)
 	qb.operator = 'A'
-	return qb
 }
 
 // Or sets the operator to 'OR', which will be used in the next condition to combine with the previous condition.
@@ -135,9 +215,89 @@ func (qb *QueryBuilder) Or() *QueryBuilder {
 	return qb
 }
 
-// Sql finalizes the query construction and returns the SQL query string and the associated arguments.
+// Sql renders the clauses in the canonical SQL order and returns the SQL query string and the associated arguments.
 // The query string will be trimmed of leading/trailing spaces.
+// Placeholders are numbered in the order they appear in the query, whatever the order of the calls was.
+// The builder stays usable, Sql may be called again after further changes.
+// The args are a copy owned by the caller, they stay valid after the builder is released.
+// It panics for UPDATE and DELETE without WHERE unless AllowFullTable was called.
 func (qb *QueryBuilder) Sql() (string, []any) {
-	sql := qb.query.String()
-	return sql, qb.args
+	q := qb.assemble()
+	return qb.dialect.render(q.sql), slices.Clone(q.args)
+}
+
+// assemble renders the clauses into out with placeholder marks
+func (qb *QueryBuilder) assemble() *buffer {
+	if (qb.stmt == 'U' || qb.stmt == 'D') && len(qb.where.sql) == 0 && !qb.fullScan {
+		panic("UPDATE or DELETE without WHERE, call AllowFullTable to affect every row")
+	}
+	out := &qb.out
+	out.reset()
+	if len(qb.with.sql) > 0 {
+		if qb.recursive {
+			out.write("WITH RECURSIVE ")
+		} else {
+			out.write("WITH ")
+		}
+	}
+	out.append(&qb.with)
+	qb.head()
+	for _, b := range [...]*buffer{
+		&qb.distinct, &qb.selects, &qb.cols, &qb.values, &qb.conflict, &qb.set, &qb.from,
+		&qb.joins, &qb.where, &qb.groupBy, &qb.having, &qb.setOps, &qb.orderBy,
+	} {
+		out.append(b)
+	}
+	qb.paging()
+	out.append(&qb.returning)
+	if qb.lock != "" {
+		out.write(qb.lock)
+		if len(qb.lockOf) > 0 {
+			out.write(" OF ")
+			out.list(qb.lockOf)
+			out.sql = out.sql[:len(out.sql)-1]
+		}
+		if qb.lockWait != "" {
+			out.writeByte(' ')
+			out.write(qb.lockWait)
+		}
+	}
+	return out
+}
+
+// head renders the statement keyword, which follows the WITH clause
+func (qb *QueryBuilder) head() {
+	switch qb.stmt {
+	case 'I':
+		qb.out.write("INSERT INTO ")
+	case 'U':
+		qb.out.write("UPDATE ")
+	case 'D':
+		qb.out.write("DELETE FROM ")
+	default:
+		if len(qb.selects.sql) > 0 || len(qb.distinct.sql) > 0 {
+			qb.out.write("SELECT ")
+		}
+		return
+	}
+	qb.out.write(qb.table)
+	qb.out.writeByte(' ')
+}
+
+// paging renders LIMIT and OFFSET, which follow ORDER BY
+func (qb *QueryBuilder) paging() {
+	if qb.limit >= 0 {
+		qb.out.write("LIMIT ")
+		qb.out.writeInt(qb.limit)
+		qb.out.writeByte(' ')
+	} else if qb.offset >= 0 && qb.dialect.noLimit != "" {
+		qb.out.write("LIMIT ")
+		qb.out.write(qb.dialect.noLimit)
+		qb.out.writeByte(' ')
+	}
+	if qb.offset >= 0 {
+		qb.out.write("OFFSET ")
+		qb.out.writeInt(qb.offset)
+		qb.out.writeByte(' ')
+	}
 }
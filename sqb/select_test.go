@@ -0,0 +1,99 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestSelect(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id", "name").
+		From("users").
+		Where("age > ?", 18).
+		Or().
+		Where("status = ?", "active").
+		Where("tags ? 'vip'").
+		Limit(10).
+		Offset(5).
+		Sql()
+	assert.Equal(t, "SELECT id, name FROM users WHERE (age > $1) OR (status = $2) AND (tags ? 'vip') LIMIT 10 OFFSET 5", sql)
+	assert.Equal(t, []any{18, "active"}, args)
+}
+
+func TestWhere_Mismatch(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.Where("a = ? AND b = ?", 1)
+	})
+}
+
+func TestSelect_AnyOrder(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Limit(10).
+		OrderBy("id", sqb.Desc).
+		Offset(20).
+		Where("age > ?", 18).
+		From("users u").
+		Select("u.id").
+		LeftJoin("orders o", "o.user_id = u.id AND o.status = ?", "paid").
+		GroupBy("u.id").
+		Select("count(o.id)").
+		Sql()
+	assert.Equal(t, "SELECT u.id, count(o.id) FROM users u LEFT JOIN orders o ON o.user_id = u.id AND o.status = $1 "+
+		"WHERE (age > $2) GROUP BY u.id ORDER BY id DESC LIMIT 10 OFFSET 20", sql)
+	assert.Equal(t, []any{"paid", 18}, args)
+}
+
+func TestSelect_Modify(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	qb.Select("id").From("users").Where("age > ?", 18).Limit(10)
+	sql, _ := qb.Sql()
+	assert.Equal(t, "SELECT id FROM users WHERE (age > $1) LIMIT 10", sql)
+
+	sql, args := qb.From("admins").Limit(-1).Offset(5).Sql()
+	assert.Equal(t, "SELECT id FROM admins WHERE (age > $1) OFFSET 5", sql)
+	assert.Equal(t, []any{18}, args)
+}
+
+func TestClone(t *testing.T) {
+	base := sqb.GetBuilder().From("users").Where("status = ?", "active")
+	defer sqb.RealiseBuilder(base)
+
+	count := base.Clone().Select("count(*)")
+	defer sqb.RealiseBuilder(count)
+	base.Select("id", "name").Where("age > ?", 18).OrderBy("id", sqb.Asc).Limit(20)
+
+	sql, args := count.Sql()
+	assert.Equal(t, "SELECT count(*) FROM users WHERE (status = $1)", sql)
+	assert.Equal(t, []any{"active"}, args)
+
+	sql, args = base.Sql()
+	assert.Equal(t, "SELECT id, name FROM users WHERE (status = $1) AND (age > $2) ORDER BY id ASC LIMIT 20", sql)
+	assert.Equal(t, []any{"active", 18}, args)
+}
+
+func TestSql_ArgsOwned(t *testing.T) {
+	qb := sqb.GetBuilder()
+	sql, args := qb.Select("id").From("users").Where("age > ?", 18).Sql()
+	_, again := qb.Where("status = ?", "active").Sql()
+	sqb.RealiseBuilder(qb)
+
+	other := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(other)
+	other.Select("id").From("orders").Where("total > ?", 100).Sql()
+
+	assert.Equal(t, "SELECT id FROM users WHERE (age > $1)", sql)
+	assert.Equal(t, []any{18}, args)
+	assert.Equal(t, []any{18, "active"}, again)
+}
@@ -0,0 +1,156 @@
+package sqb
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TagDB names the column of a struct field, fields without it are ignored:
+//
+//	type User struct {
+//		ID        int64     `db:"id,pk"`
+//		Name      string    `db:"name"`
+//		CreatedAt time.Time `db:"created_at,readonly"`
+//	}
+//
+// pk columns are left out of UpdateStruct, readonly ones, filled by the database, out of every write.
+// Embedded structs contribute their columns as if they were fields of the outer struct.
+const TagDB = "db"
+
+type structMeta struct {
+	fields   []fieldMeta
+	columns  []string
+	insert   []string
+	byColumn map[string]int
+}
+
+type fieldMeta struct {
+	column   string
+	index    []int
+	pk       bool
+	readonly bool
+}
+
+var structCache sync.Map
+
+// Columns returns the columns of T in field order, for Select and the matching ScanTargets.
+// The returned slice is shared and must not be modified.
+func Columns[T any]() []string {
+	return metaOf(reflect.TypeFor[T]()).columns
+}
+
+// ScanTargets returns pointers to the fields of dst in the order of Columns, for rows.Scan:
+//
+//	rows.Scan(sqb.ScanTargets(&user)...)
+func ScanTargets[T any](dst *T) []any {
+	rv := reflect.ValueOf(dst).Elem()
+	meta := metaOf(rv.Type())
+	targets := make([]any, len(meta.fields))
+	for i, f := range meta.fields {
+		targets[i] = rv.FieldByIndex(f.index).Addr().Interface()
+	}
+	return targets
+}
+
+// InsertStruct adds the columns and a row of values taken from v, a struct or a pointer to one.
+// It may be called repeatedly after Insert to insert many rows, the columns are written by the first call.
+func (qb *QueryBuilder) InsertStruct(v any) *QueryBuilder {
+	rv := structValue(v)
+	meta := metaOf(rv.Type())
+	if qb.rows == 0 && qb.columns == 0 {
+		qb.Columns(meta.insert...)
+	}
+	values := make([]any, 0, len(meta.insert))
+	for _, f := range meta.fields {
+		if !f.readonly {
+			values = append(values, rv.FieldByIndex(f.index).Interface())
+		}
+	}
+	return qb.Values(values...)
+}
+
+// UpdateStruct adds a Set for every column of v, a struct or a pointer to one, except pk and readonly ones.
+// onlyNonZero skips fields holding zero values, for partial updates.
+// With every field zero nothing is set, check HasSet before running the query.
+func (qb *QueryBuilder) UpdateStruct(v any, onlyNonZero bool) *QueryBuilder {
+	rv := structValue(v)
+	for _, f := range metaOf(rv.Type()).fields {
+		if f.pk || f.readonly {
+			continue
+		}
+		fv := rv.FieldByIndex(f.index)
+		if onlyNonZero && fv.IsZero() {
+			continue
+		}
+		qb.Set(f.column, fv.Interface())
+	}
+	return qb
+}
+
+func structValue(v any) reflect.Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic("sqb: struct or pointer to struct expected, got " + rv.Kind().String())
+	}
+	return rv
+}
+
+func metaOf(rt reflect.Type) *structMeta {
+	if meta, ok := structCache.Load(rt); ok {
+		return meta.(*structMeta)
+	}
+	if rt.Kind() != reflect.Struct {
+		panic("sqb: struct expected, got " + rt.String())
+	}
+	meta := &structMeta{
+		byColumn: make(map[string]int),
+	}
+	collectFields(rt, nil, meta)
+	for i, f := range meta.fields {
+		meta.byColumn[f.column] = i
+		meta.columns = append(meta.columns, f.column)
+		if !f.readonly {
+			meta.insert = append(meta.insert, f.column)
+		}
+	}
+	actual, _ := structCache.LoadOrStore(rt, meta)
+	return actual.(*structMeta)
+}
+
+func collectFields(rt reflect.Type, index []int, meta *structMeta) {
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		tag, hasTag := field.Tag.Lookup(TagDB)
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(index[:len(index):len(index)], i)
+		if !hasTag && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectFields(field.Type, fieldIndex, meta)
+			continue
+		}
+		if !hasTag || !field.IsExported() {
+			continue
+		}
+		column, opts, _ := strings.Cut(tag, ",")
+		f := fieldMeta{
+			column: column,
+			index:  fieldIndex,
+		}
+		for opts != "" {
+			var opt string
+			opt, opts, _ = strings.Cut(opts, ",")
+			switch strings.TrimSpace(opt) {
+			case "pk":
+				f.pk = true
+			case "readonly":
+				f.readonly = true
+			}
+		}
+		meta.fields = append(meta.fields, f)
+	}
+}
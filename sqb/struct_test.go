@@ -0,0 +1,94 @@
+package sqb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+type timestamps struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type user struct {
+	ID    int64  `db:"id,pk"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+	Note  string `db:"-"`
+	cache string
+	timestamps
+}
+
+func TestColumns(t *testing.T) {
+	assert.Equal(t, []string{"id", "name", "email", "created_at", "updated_at"}, sqb.Columns[user]())
+}
+
+func TestScanTargets(t *testing.T) {
+	var u user
+	targets := sqb.ScanTargets(&u)
+	assert.Len(t, targets, 5)
+
+	*targets[0].(*int64) = 7
+	*targets[1].(*string) = "alice"
+	*targets[3].(*time.Time) = time.Unix(1, 0)
+	assert.Equal(t, int64(7), u.ID)
+	assert.Equal(t, "alice", u.Name)
+	assert.Equal(t, time.Unix(1, 0), u.CreatedAt)
+}
+
+func TestInsertStruct(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	at := time.Unix(100, 0)
+	sql, args := qb.Insert("users").
+		InsertStruct(user{ID: 1, Name: "alice", Email: "a@x", timestamps: timestamps{UpdatedAt: at}}).
+		InsertStruct(&user{ID: 2, Name: "bob"}).
+		Returning(sqb.Columns[user]()...).
+		Sql()
+	assert.Equal(t, "INSERT INTO users (id, name, email, updated_at) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8) "+
+		"RETURNING id, name, email, created_at, updated_at", sql)
+	assert.Equal(t, []any{int64(1), "alice", "a@x", at, int64(2), "bob", "", time.Time{}}, args)
+}
+
+func TestUpdateStruct(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Update("users").
+		UpdateStruct(&user{ID: 1, Name: "alice"}, true).
+		Where("id = ?", 1).
+		Sql()
+	assert.Equal(t, "UPDATE users SET name = $1 WHERE (id = $2)", sql)
+	assert.Equal(t, []any{"alice", 1}, args)
+
+	qb.Reset()
+	sql, _ = qb.Update("users").UpdateStruct(user{}, false).Where("id = ?", 1).Sql()
+	assert.Equal(t, "UPDATE users SET name = $1, email = $2, updated_at = $3 WHERE (id = $4)", sql)
+}
+
+func TestUpdateStruct_NothingToSet(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	qb.Update("users").UpdateStruct(&user{ID: 1}, true)
+	assert.False(t, qb.HasSet())
+
+	qb.Reset()
+	sql, _ := qb.Update("users").Set("name", "alice").UpdateStruct(&user{ID: 1}, true).Where("id = ?", 1).Sql()
+	assert.True(t, qb.HasSet())
+	assert.Equal(t, "UPDATE users SET name = $1 WHERE (id = $2)", sql)
+}
+
+func TestInsertStruct_NotStruct(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.Insert("users").InsertStruct(42)
+	})
+}
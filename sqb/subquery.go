@@ -0,0 +1,106 @@
+package sqb
+
+// FromSubquery sets the FROM clause to (sub) AS alias. sub is rendered right away and released to the pool.
+func (qb *QueryBuilder) FromSubquery(sub *QueryBuilder, alias string) *QueryBuilder {
+	qb.from.reset()
+	qb.from.write("FROM (")
+	qb.from.embed(sub)
+	qb.from.write(") AS ")
+	qb.from.write(alias)
+	qb.from.writeByte(' ')
+	return qb
+}
+
+// With adds the common table expression name AS (sub) to the WITH clause.
+// name may list the columns like "tree(id, parent_id)". sub is rendered right away and released to the pool.
+func (qb *QueryBuilder) With(name string, sub *QueryBuilder) *QueryBuilder {
+	return qb.cte(name, sub)
+}
+
+// WithRecursive adds a common table expression like With and turns the WITH clause into WITH RECURSIVE.
+func (qb *QueryBuilder) WithRecursive(name string, sub *QueryBuilder) *QueryBuilder {
+	qb.recursive = true
+	return qb.cte(name, sub)
+}
+
+func (qb *QueryBuilder) cte(name string, sub *QueryBuilder) *QueryBuilder {
+	if len(qb.with.sql) > 0 {
+		qb.with.comma()
+	}
+	qb.with.write(name)
+	qb.with.write(" AS (")
+	qb.with.embed(sub)
+	qb.with.write(") ")
+	return qb
+}
+
+// Union appends UNION sub, combining the rows of both queries without duplicates.
+func (qb *QueryBuilder) Union(sub *QueryBuilder) *QueryBuilder {
+	return qb.compound("UNION ", sub)
+}
+
+// UnionAll appends UNION ALL sub, keeping duplicate rows.
+func (qb *QueryBuilder) UnionAll(sub *QueryBuilder) *QueryBuilder {
+	return qb.compound("UNION ALL ", sub)
+}
+
+// Intersect appends INTERSECT sub, keeping the rows returned by both queries.
+func (qb *QueryBuilder) Intersect(sub *QueryBuilder) *QueryBuilder {
+	return qb.compound("INTERSECT ", sub)
+}
+
+// Except appends EXCEPT sub, removing the rows returned by sub.
+func (qb *QueryBuilder) Except(sub *QueryBuilder) *QueryBuilder {
+	return qb.compound("EXCEPT ", sub)
+}
+
+// compound appends the operand sub, parenthesised when it has its own ORDER BY, LIMIT or OFFSET,
+// which would apply to the whole compound query otherwise. Dialects without parenthesised operands panic then.
+func (qb *QueryBuilder) compound(op string, sub *QueryBuilder) *QueryBuilder {
+	qb.setOps.write(op)
+	if len(sub.orderBy.sql) == 0 && sub.limit < 0 && sub.offset < 0 {
+		qb.setOps.embed(sub)
+		qb.setOps.writeByte(' ')
+		return qb
+	}
+	if !qb.dialect.parenOps {
+		panic("ORDER BY, LIMIT and OFFSET of compound operands are not supported by " + qb.dialect.name)
+	}
+	qb.setOps.writeByte('(')
+	qb.setOps.embed(sub)
+	qb.setOps.write(") ")
+	return qb
+}
+
+type subqueryExpr struct {
+	prefix string
+	sub    *buffer
+}
+
+// InQuery renders col IN (sub). sub is rendered right away and released to the pool,
+// so that the expression can be reused, e.g. by a count and a page query.
+func InQuery(col string, sub *QueryBuilder) Expr {
+	return subquery(col+" IN (", sub)
+}
+
+// Exists renders EXISTS (sub), sub is rendered right away and released to the pool like for InQuery.
+func Exists(sub *QueryBuilder) Expr {
+	return subquery("EXISTS (", sub)
+}
+
+func subquery(prefix string, sub *QueryBuilder) Expr {
+	if sub == nil {
+		return subqueryExpr{}
+	}
+	return subqueryExpr{prefix, rendered(sub)}
+}
+
+func (e subqueryExpr) appendExpr(_ *QueryBuilder, b *buffer) {
+	b.write(e.prefix)
+	b.append(e.sub)
+	b.writeByte(')')
+}
+
+func (e subqueryExpr) empty() bool {
+	return e.sub == nil
+}
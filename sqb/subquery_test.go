@@ -0,0 +1,113 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestSubquery_Where(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	banned := sqb.GetBuilder().Select("user_id").From("bans").Where("reason = ?", "spam")
+	paid := sqb.GetBuilder().Select("1").From("orders o").Where("o.user_id = u.id AND o.total > ?", 100)
+	sql, args := qb.Select("id").
+		From("users u").
+		Where("u.age > ?", 18).
+		Where("u.id NOT IN (?) AND u.note <> '$1'", banned).
+		WhereExpr(sqb.Exists(paid)).
+		Sql()
+	assert.Equal(t, "SELECT id FROM users u WHERE (u.age > $1) "+
+		"AND (u.id NOT IN (SELECT user_id FROM bans WHERE (reason = $2)) AND u.note <> '$1') "+
+		"AND (EXISTS (SELECT 1 FROM orders o WHERE (o.user_id = u.id AND o.total > $3)))", sql)
+	assert.Equal(t, []any{18, "spam", 100}, args)
+}
+
+func TestSubquery_From(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	totals := sqb.GetBuilder().
+		Select("user_id", "sum(total) AS total").
+		From("orders").
+		Where("created_at > ?", "2024-01-01").
+		GroupBy("user_id")
+	sql, args := qb.Select("t.user_id").
+		FromSubquery(totals, "t").
+		Join("users u", "u.id = t.user_id").
+		Where("t.total > ?", 1000).
+		Sql()
+	assert.Equal(t, "SELECT t.user_id FROM (SELECT user_id, sum(total) AS total FROM orders "+
+		"WHERE (created_at > $1) GROUP BY user_id) AS t JOIN users u ON u.id = t.user_id WHERE (t.total > $2)", sql)
+	assert.Equal(t, []any{"2024-01-01", 1000}, args)
+}
+
+func TestSubquery_With(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	active := sqb.GetBuilder().Select("id").From("users").Where("status = ?", "active")
+	tree := sqb.GetBuilder().
+		Select("id", "parent_id").From("nodes").Where("id = ?", 1).
+		UnionAll(sqb.GetBuilder().Select("n.id", "n.parent_id").From("nodes n").Join("tree t", "n.parent_id = t.id"))
+	sql, args := qb.With("active", active).
+		WithRecursive("tree(id, parent_id)", tree).
+		Select("id").
+		From("tree").
+		Where("id IN (?)", sqb.GetBuilder().Select("id").From("active").Limit(10)).
+		Sql()
+	assert.Equal(t, "WITH RECURSIVE active AS (SELECT id FROM users WHERE (status = $1)), "+
+		"tree(id, parent_id) AS (SELECT id, parent_id FROM nodes WHERE (id = $2) "+
+		"UNION ALL SELECT n.id, n.parent_id FROM nodes n JOIN tree t ON n.parent_id = t.id) "+
+		"SELECT id FROM tree WHERE (id IN (SELECT id FROM active LIMIT 10))", sql)
+	assert.Equal(t, []any{"active", 1}, args)
+}
+
+func TestSubquery_SetOperations(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Select("id").From("a").Where("x = ?", 1).
+		Union(sqb.GetBuilder().Select("id").From("b").Where("y = ?", 2)).
+		Intersect(sqb.GetBuilder().Select("id").From("c")).
+		Except(sqb.GetBuilder().Select("id").From("d").Where("z = ?", 3)).
+		Sql()
+	assert.Equal(t, "SELECT id FROM a WHERE (x = $1) UNION SELECT id FROM b WHERE (y = $2) "+
+		"INTERSECT SELECT id FROM c EXCEPT SELECT id FROM d WHERE (z = $3)", sql)
+	assert.Equal(t, []any{1, 2, 3}, args)
+}
+
+func TestSubquery_SetOperationsPaged(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, _ := qb.Select("id").From("b").
+		Union(sqb.GetBuilder().Select("id").From("a").OrderBy("id", sqb.Asc).Limit(5)).
+		OrderBy("id", sqb.Desc).
+		Sql()
+	assert.Equal(t, "SELECT id FROM b UNION (SELECT id FROM a ORDER BY id ASC LIMIT 5) ORDER BY id DESC", sql)
+
+	qb.Reset()
+	qb.SetDialect(sqb.SQLite).Select("id").From("b")
+	assert.Panics(t, func() {
+		qb.UnionAll(sqb.GetBuilder().Select("id").From("a").Offset(5))
+	})
+}
+
+func TestSubquery_ExprReused(t *testing.T) {
+	filter := sqb.And(
+		sqb.InQuery("team_id", sqb.GetBuilder().Select("id").From("teams").Where("region = ?", "eu")),
+		sqb.Raw("owner_id = (?)", sqb.GetBuilder().Select("id").From("owners").Where("name = ?", "bob")),
+	)
+	for range 2 {
+		count := sqb.GetBuilder()
+		sql, args := count.Select("count(*)").From("users").WhereExpr(filter).Sql()
+		sqb.RealiseBuilder(count)
+		assert.Equal(t, "SELECT count(*) FROM users WHERE (team_id IN (SELECT id FROM teams WHERE (region = $1)) "+
+			"AND (owner_id = (SELECT id FROM owners WHERE (name = $2))))", sql)
+		assert.Equal(t, []any{"eu", "bob"}, args)
+	}
+}
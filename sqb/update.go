@@ -0,0 +1,77 @@
+package sqb
+
+import (
+	"maps"
+	"slices"
+)
+
+// Update starts an UPDATE statement of the specified table.
+// Its clauses are rendered in the order UPDATE, Set..., From, Where, Returning.
+func (qb *QueryBuilder) Update(table string) *QueryBuilder {
+	qb.stmt = 'U'
+	qb.table = table
+	return qb
+}
+
+// Set adds col = value to the SET clause.
+func (qb *QueryBuilder) Set(col string, value any) *QueryBuilder {
+	qb.setColumn(col)
+	qb.set.arg(value)
+	qb.set.writeByte(' ')
+	return qb
+}
+
+// SetMap adds every column of values to the SET clause, sorted by column name for a stable query.
+func (qb *QueryBuilder) SetMap(values map[string]any) *QueryBuilder {
+	for _, col := range slices.Sorted(maps.Keys(values)) {
+		qb.Set(col, values[col])
+	}
+	return qb
+}
+
+// SetExpr adds col = expr to the SET clause, expr may contain '?' placeholders like Where,
+// e.g. SetExpr("counter", "counter + ?", 1).
+func (qb *QueryBuilder) SetExpr(col, expr string, args ...any) *QueryBuilder {
+	qb.setColumn(col)
+	qb.set.bind(expr, args)
+	qb.set.writeByte(' ')
+	return qb
+}
+
+// HasSet reports whether the SET clause has any column, e.g. to answer an empty partial update without running it.
+func (qb *QueryBuilder) HasSet() bool {
+	return len(qb.set.sql) > 0
+}
+
+func (qb *QueryBuilder) setColumn(col string) {
+	if len(qb.set.sql) == 0 {
+		qb.set.write("SET ")
+	} else {
+		qb.set.comma()
+	}
+	qb.set.write(col)
+	qb.set.write(" = ")
+}
+
+// DeleteFrom starts a DELETE statement of the specified table.
+// Its clauses are rendered in the order DELETE FROM, Using, Where, Returning.
+func (qb *QueryBuilder) DeleteFrom(table string) *QueryBuilder {
+	qb.stmt = 'D'
+	qb.table = table
+	return qb
+}
+
+// Using sets the USING clause joining the specified tables into DELETE.
+// UPDATE joins other tables with From instead.
+func (qb *QueryBuilder) Using(tables ...string) *QueryBuilder {
+	qb.from.reset()
+	qb.from.write("USING ")
+	qb.from.list(tables)
+	return qb
+}
+
+// AllowFullTable lets UPDATE or DELETE without WHERE render, affecting every row of the table.
+func (qb *QueryBuilder) AllowFullTable() *QueryBuilder {
+	qb.fullScan = true
+	return qb
+}
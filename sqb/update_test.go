@@ -0,0 +1,71 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xakepp35/pkg/sqb"
+)
+
+func TestUpdate(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Update("users").
+		Set("name", "alice").
+		SetExpr("visits", "visits + ?", 1).
+		SetMap(map[string]any{"status": "active", "age": 30}).
+		Where("id = ?", 7).
+		Returning("id", "visits").
+		Sql()
+	assert.Equal(t, "UPDATE users SET name = $1, visits = visits + $2, age = $3, status = $4 "+
+		"WHERE (id = $5) RETURNING id, visits", sql)
+	assert.Equal(t, []any{"alice", 1, 30, "active", 7}, args)
+}
+
+func TestUpdate_From(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.Update("orders o").
+		SetExpr("total", "o.total * (1 - d.rate)").
+		From("discounts d").
+		Where("d.customer_id = o.customer_id AND d.code = ?", "SPRING").
+		Sql()
+	assert.Equal(t, "UPDATE orders o SET total = o.total * (1 - d.rate) FROM discounts d "+
+		"WHERE (d.customer_id = o.customer_id AND d.code = $1)", sql)
+	assert.Equal(t, []any{"SPRING"}, args)
+}
+
+func TestDeleteFrom(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	sql, args := qb.DeleteFrom("sessions s").
+		Using("users u").
+		Where("s.user_id = u.id").
+		Where("u.banned = ?", true).
+		Returning("s.id").
+		Sql()
+	assert.Equal(t, "DELETE FROM sessions s USING users u WHERE (s.user_id = u.id) AND (u.banned = $1) RETURNING s.id", sql)
+	assert.Equal(t, []any{true}, args)
+}
+
+func TestFullTableGuard(t *testing.T) {
+	qb := sqb.GetBuilder()
+	defer sqb.RealiseBuilder(qb)
+
+	assert.Panics(t, func() {
+		qb.Update("users").Set("active", false).Sql()
+	})
+
+	qb.Reset()
+	assert.Panics(t, func() {
+		qb.DeleteFrom("users").Sql()
+	})
+
+	qb.Reset()
+	sql, _ := qb.DeleteFrom("users").AllowFullTable().Sql()
+	assert.Equal(t, "DELETE FROM users", sql)
+}
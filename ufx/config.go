@@ -0,0 +1,49 @@
+package ufx
+
+import (
+	"reflect"
+
+	"go.uber.org/fx"
+
+	"github.com/xakepp35/pkg/env"
+	"github.com/xakepp35/pkg/xlog"
+)
+
+// Config provides *T loaded from env with env.Load when the graph is constructed,
+// so tests can still override env before fx.New. Validation errors fail fx.New.
+func Config[T any]() fx.Option {
+	return fx.Provide(NewConfig[T])
+}
+
+// NamedConfig provides *T of a multi-instance module tagged as name:"<name>",
+// loaded through env.Scope(prefix, name). An empty name provides an untagged *T.
+func NamedConfig[T any](prefix, name string) fx.Option {
+	scope := env.Scope(prefix, name)
+	constructor := func() (*T, error) {
+		return loadConfig[T](scope.Load, scope.Redact)
+	}
+	if name == "" {
+		return fx.Provide(constructor)
+	}
+	return fx.Provide(
+		fx.Annotate(constructor, fx.ResultTags(`name:"`+name+`"`)),
+	)
+}
+
+// NewConfig loads and validates T, logging a redacted summary
+func NewConfig[T any]() (*T, error) {
+	return loadConfig[T](env.Load, env.Redact)
+}
+
+func loadConfig[T any](load func(dst any) error, redact func(cfg any) any) (*T, error) {
+	cfg := new(T)
+	err := load(cfg)
+	xlog.ErrInfo(err).
+		Str("type", reflect.TypeFor[T]().String()).
+		Any("cfg", redact(cfg)).
+		Msg("env.Load")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
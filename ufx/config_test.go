@@ -0,0 +1,57 @@
+package ufx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/xakepp35/pkg/ufx"
+)
+
+type testConfig struct {
+	DSN  string `env:"DSN" default:"postgres://localhost" secret:"true"`
+	Size int    `env:"POOL_SIZE" default:"4" validate:"min=1"`
+}
+
+func TestConfig(t *testing.T) {
+	t.Setenv("POOL_SIZE", "8")
+
+	var cfg *testConfig
+	app := fx.New(
+		ufx.Config[testConfig](),
+		fx.Populate(&cfg),
+	)
+	require.NoError(t, app.Err())
+	assert.Equal(t, 8, cfg.Size)
+}
+
+func TestConfig_Invalid(t *testing.T) {
+	t.Setenv("POOL_SIZE", "0")
+
+	app := fx.New(
+		ufx.Config[testConfig](),
+		fx.Invoke(func(*testConfig) {}),
+	)
+	assert.ErrorContains(t, app.Err(), "rule=min=1")
+}
+
+func TestNamedConfig(t *testing.T) {
+	t.Setenv("UFX_POOL_SIZE", "2")
+	t.Setenv("UFX_POOL_SIZE_REPLICA", "3")
+
+	var cfgs struct {
+		fx.In
+		Primary *testConfig
+		Replica *testConfig `name:"replica"`
+	}
+	app := fx.New(
+		ufx.NamedConfig[testConfig]("UFX", ""),
+		ufx.NamedConfig[testConfig]("UFX", "replica"),
+		fx.Populate(&cfgs),
+	)
+	require.NoError(t, app.Err())
+	assert.Equal(t, 2, cfgs.Primary.Size)
+	assert.Equal(t, 3, cfgs.Replica.Size)
+}
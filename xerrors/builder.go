@@ -38,6 +38,8 @@ type errorBuilder struct {
 func (e *errorBuilder) resetSelf() {
 	e.errBuffer = e.errBuffer[:0]
 	e.argsBuffer = e.argsBuffer[:0]
+	e.err = nil
+	e.code = nil
 	buildersPool.Put(e)
 }
 
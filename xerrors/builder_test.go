@@ -123,3 +123,13 @@ func TestErrBuilder_Proto(t *testing.T) {
 		}
 	})
 }
+
+func TestErrBuilder_ResetsCode(t *testing.T) {
+	orig := errors.New("foo")
+	_ = Err(orig).Proto(codes.AlreadyExists)
+
+	err := Err(orig).Msg("bar")
+	grpcStatus, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.OK, grpcStatus.Code(), "code must not leak from a pooled builder")
+}
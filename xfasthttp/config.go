@@ -3,11 +3,17 @@ package xfasthttp
 import "github.com/xakepp35/pkg/env"
 
 type ServerConfig struct {
-	Addr string
+	Addr string `validate:"hostport"`
 }
 
 func NewServerConfig() *ServerConfig {
+	env.Describe("PORT", "HTTP listen port")
 	return &ServerConfig{
 		Addr: ":" + env.String("PORT", "8080"),
 	}
 }
+
+// Validate checks the listen address
+func (c *ServerConfig) Validate() error {
+	return env.Validate(c)
+}
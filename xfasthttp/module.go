@@ -3,14 +3,13 @@ package xfasthttp
 import "go.uber.org/fx"
 
 var Module = fx.Module("xfasthttp",
-	fx.Supply(
-		NewServerConfig(),
-	),
 	fx.Provide(
+		NewServerConfig,
 		NewServer,
 		NewLifecycle,
 	),
 	fx.Invoke(
+		(*ServerConfig).Validate,
 		RunServer,
 	),
 )
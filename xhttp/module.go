@@ -8,10 +8,13 @@ import (
 )
 
 var Module = fx.Module("xhttp",
-	fx.Supply(
-		NewServerConfig(),
+	fx.Provide(
+		NewServerConfig,
+	),
+	fx.Invoke(
+		(*ServerConfig).Validate,
+		Run,
 	),
-	fx.Invoke(Run),
 )
 
 func Run(lc fx.Lifecycle, mux chi.Router, cfg *ServerConfig) {
@@ -10,19 +10,25 @@ import (
 )
 
 type ServerConfig struct {
-	Addr string `json:"addr"`
+	Addr string `json:"addr" validate:"hostport"`
 }
 
 func NewServerConfig() *ServerConfig {
+	env.Describe("PORT", "HTTP listen port")
 	return &ServerConfig{
 		Addr: ":" + env.String("PORT", "8080"),
 	}
 }
 
+// Validate checks the listen address
+func (c *ServerConfig) Validate() error {
+	return env.Validate(c)
+}
+
 // startHTTPServer запускает HTTP-сервер.
 func RunServer(cfg *ServerConfig, mux chi.Router) {
 	log.Info().
-		Any("cfg", cfg).
+		Any("cfg", env.Redact(cfg)).
 		Msg("xhttp.StartServer")
 	if err := http.ListenAndServe(cfg.Addr, mux); err != nil {
 		log.Fatal().
@@ -0,0 +1,70 @@
+package xpgx
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/xakepp35/pkg/env"
+	"github.com/xakepp35/pkg/xerrors"
+)
+
+// Config is the config block of a pool, read through env.Scope(ScopePrefix, poolName),
+// so PG_MAX_CONNS_REPLICA overrides PG_MAX_CONNS for the replica pool only.
+// Zero values keep the defaults of pgxpool and of the DSN parameters.
+type Config struct {
+	DSN               string        `env:"DSN" secret:"true" validate:"nonzero" desc:"Postgres connection string"`
+	MaxConns          int32         `env:"MAX_CONNS" validate:"min=0" desc:"maximum size of the pool"`
+	MinConns          int32         `env:"MIN_CONNS" validate:"min=0" desc:"minimum size of the pool"`
+	MaxConnLifetime   time.Duration `env:"MAX_CONN_LIFETIME" validate:"min=0s" desc:"duration since creation after which a connection is closed"`
+	MaxConnIdleTime   time.Duration `env:"MAX_CONN_IDLE_TIME" validate:"min=0s" desc:"duration after which an idle connection is closed"`
+	HealthCheckPeriod time.Duration `env:"HEALTH_CHECK_PERIOD" validate:"min=0s" desc:"duration between health checks of idle connections"`
+	ConnectTimeout    time.Duration `env:"CONNECT_TIMEOUT" validate:"min=0s" desc:"timeout of establishing a connection"`
+}
+
+// NewConfig reads the config block of the pool named poolName.
+// The DSN is checked with pgxpool.ParseConfig, so both URLs and keyword/value strings are accepted.
+func NewConfig(poolName string) (*Config, error) {
+	scope := env.Scope(ScopePrefix, poolName)
+	scope.MarkSecret("DSN")
+	cfg := &Config{
+		DSN: DefaultDSN,
+	}
+	if err := scope.Load(cfg); err != nil {
+		return nil, err
+	}
+	if _, err := pgxpool.ParseConfig(cfg.DSN); err != nil {
+		return nil, xerrors.Err(env.ErrValidation).
+			Str("key", scope.Resolve("DSN")).
+			Str("rule", "dsn").
+			Str("value", env.Redacted).
+			Send()
+	}
+	return cfg, nil
+}
+
+// PoolConfig parses the DSN and applies the pool settings on top of it
+func (c *Config) PoolConfig() (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(c.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxConns > 0 {
+		cfg.MaxConns = c.MaxConns
+	}
+	if c.MinConns > 0 {
+		cfg.MinConns = c.MinConns
+	}
+	if c.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = c.MaxConnLifetime
+	}
+	if c.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = c.MaxConnIdleTime
+	}
+	if c.HealthCheckPeriod > 0 {
+		cfg.HealthCheckPeriod = c.HealthCheckPeriod
+	}
+	if c.ConnectTimeout > 0 {
+		cfg.ConnConfig.ConnectTimeout = c.ConnectTimeout
+	}
+	return cfg, nil
+}
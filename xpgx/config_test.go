@@ -0,0 +1,35 @@
+package xpgx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xakepp35/pkg/xpgx"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Setenv("PG_DSN", "postgres://app@primary:5432/app")
+	t.Setenv("PG_MAX_CONNS", "8")
+	t.Setenv("PG_MAX_CONNS_REPLICA", "16")
+	t.Setenv("PG_CONNECT_TIMEOUT", "2s")
+
+	cfg, err := xpgx.NewConfig("replica")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app@primary:5432/app", cfg.DSN)
+	assert.Equal(t, int32(16), cfg.MaxConns)
+
+	poolCfg, err := cfg.PoolConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "primary", poolCfg.ConnConfig.Host)
+	assert.Equal(t, int32(16), poolCfg.MaxConns)
+	assert.Equal(t, 2*time.Second, poolCfg.ConnConfig.ConnectTimeout)
+}
+
+func TestNewConfig_Invalid(t *testing.T) {
+	t.Setenv("PG_MAX_CONNS_BROKEN", "-1")
+
+	_, err := xpgx.NewConfig("broken")
+	assert.ErrorContains(t, err, "PG_MAX_CONNS_BROKEN")
+}
@@ -1,16 +1,12 @@
 package xpgx
 
-import (
-	"strings"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/xakepp35/pkg/env"
-	"go.uber.org/fx"
-)
+import "go.uber.org/fx"
 
 func NewModule(poolName string) fx.Option {
-	dsnKey := genDsnKey(poolName)
-	dsnEnv := env.String(dsnKey, DefaultDSN)
+	cfg, err := NewConfig(poolName)
+	if err != nil {
+		return fx.Error(err)
+	}
 	tagDsn := `name:"pg_dsn_` + poolName + `"`
 	tagCfg := `name:"pg_cfg_` + poolName + `"`
 	tagPool := genPoolTag(poolName)
@@ -19,10 +15,10 @@ func NewModule(poolName string) fx.Option {
 	tagTxm := genTxmTag(poolName)
 	return fx.Module("xpgx-"+poolName,
 		fx.Supply(
-			fx.Annotate(dsnEnv, fx.ResultTags(tagDsn)),
+			fx.Annotate(cfg.DSN, fx.ResultTags(tagDsn)),
 		),
 		fx.Provide(
-			fx.Annotate(pgxpool.ParseConfig, fx.ParamTags(tagDsn), fx.ResultTags(tagCfg)),
+			fx.Annotate(cfg.PoolConfig, fx.ResultTags(tagCfg)),
 			fx.Annotate(NewTracer, fx.ResultTags(tagTrc)),
 		),
 		fx.Invoke(
@@ -41,13 +37,6 @@ func NewModule(poolName string) fx.Option {
 	)
 }
 
-func genDsnKey(poolName string) string {
-	if poolName == "" {
-		return DefaultDSNKey
-	}
-	return DefaultDSNKey + "_" + strings.ToUpper(poolName)
-}
-
 func genPoolTag(name string) string {
 	if name == "" {
 		return ""
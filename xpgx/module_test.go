@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/xakepp35/pkg/env"
 	"github.com/xakepp35/pkg/xpgx"
 	"go.uber.org/fx"
 )
@@ -11,3 +12,15 @@ import (
 func TestNewModule(t *testing.T) {
 	assert.NoError(t, fx.New(xpgx.NewModule("")).Err())
 }
+
+func TestNewModule_KeywordDSN(t *testing.T) {
+	t.Setenv("PG_DSN_KEYWORD", "host=localhost user=postgres dbname=postgres")
+	assert.NoError(t, fx.New(xpgx.NewModule("keyword")).Err())
+}
+
+func TestNewModule_InvalidDSN(t *testing.T) {
+	t.Setenv("PG_DSN_BROKEN", "localhost/db")
+	err := fx.New(xpgx.NewModule("broken")).Err()
+	assert.ErrorIs(t, err, env.ErrValidation)
+	assert.Contains(t, err.Error(), "PG_DSN_BROKEN")
+}
@@ -2,10 +2,13 @@ package xpgx
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/xakepp35/pkg/sqb"
 	"github.com/xakepp35/pkg/xlog"
 )
 
@@ -42,6 +45,10 @@ func (s *tracerQuery) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pg
 	duration := time.Since(traceData.StartedAt)
 	xlog.ErrDebug(data.Err).
 		Str("sql", traceData.Sql).
+		Func(func(e *zerolog.Event) {
+			// the shape of the query, to aggregate stats of queries differing only in values
+			e.Str("fingerprint", strconv.FormatUint(sqb.Fingerprint(traceData.Sql), 16))
+		}).
 		Any("args", traceData.Args).
 		Dur("cost", duration).
 		Str("tag", data.CommandTag.String()).
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rqlite/gorqlite"
 	"github.com/rs/zerolog/log"
+	"github.com/xakepp35/pkg/env"
 	"github.com/xakepp35/pkg/xlog"
 	"go.uber.org/fx"
 )
@@ -15,7 +16,7 @@ type Lifecycle struct {
 
 func NewConnection(connURL string) (*gorqlite.Connection, error) {
 	res, err := gorqlite.OpenWithClient(connURL, gorqlite.DefaultHTTPClient)
-	xlog.ErrInfo(err).Str("connURL", connURL).Msg("gorqlite.OpenWithClient")
+	xlog.ErrInfo(err).Str("connURL", env.RedactURL(connURL)).Msg("gorqlite.OpenWithClient")
 	if err != nil {
 		return nil, err
 	}
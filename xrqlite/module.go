@@ -1,16 +1,16 @@
 package xrqlite
 
 import (
-	"strings"
-
 	"github.com/xakepp35/pkg/env"
 	"go.uber.org/fx"
 )
 
 // Module provides the RQLite dependencies to Uber FX
 func NewModule(name string) fx.Option {
-	dsnKey := genConnURL(name)
-	dsnEnv := env.String(dsnKey, DefaultConnURL)
+	scope := env.Scope(ScopePrefix, name)
+	scope.MarkSecret(ConnURLKey)
+	scope.Describe(ConnURLKey, "rqlite connection URL")
+	dsnEnv := scope.String(ConnURLKey, DefaultConnURL)
 	tagDsn := `name:"rq_dsn_` + name + `"`
 	tagConn := genConnTag(name)
 	tagRqlc := `name:"rq_lc_` + name + `"`
@@ -28,13 +28,6 @@ func NewModule(name string) fx.Option {
 	)
 }
 
-func genConnURL(name string) string {
-	if name == "" {
-		return DefaultConnURLKey
-	}
-	return DefaultConnURLKey + "_" + strings.ToUpper(name)
-}
-
 func genConnTag(name string) string {
 	if name == "" {
 		return ""
@@ -43,6 +36,9 @@ func genConnTag(name string) string {
 }
 
 const (
+	// ScopePrefix and ConnURLKey form the env keys of the connections, like DB_DSN_<NAME>
+	ScopePrefix       = "DB"
+	ConnURLKey        = "DSN"
 	DefaultConnURLKey = "DB_DSN"
 	DefaultConnURL    = "http://localhost"
 )